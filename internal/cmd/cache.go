@@ -0,0 +1,79 @@
+/*
+ * Copyright (C) 2026 Simone Pezzano
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/theirish81/meta/internal/config"
+	"github.com/theirish81/meta/internal/persistence/connection"
+	"github.com/theirish81/meta/internal/persistence/services"
+	"gorm.io/gorm/logger"
+)
+
+var (
+	providerParam string
+	modelParam    string
+)
+
+var cache = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the embedding cache",
+}
+
+var cachePrune = &cobra.Command{
+	Use:   "prune",
+	Short: "Delete cached embeddings. Without flags, deletes the embeddings of every model but the configured one",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := initPersistence(); err != nil {
+			cmd.PrintErrln(err)
+			return
+		}
+		cacheService := services.NewEmbeddingCacheService()
+		var deleted int64
+		var err error
+		if modelParam != "" || providerParam != "" {
+			deleted, err = cacheService.Prune(context.Background(), providerParam, modelParam)
+		} else {
+			deleted, err = cacheService.PruneExcept(context.Background(), config.Instance.EmbeddingService,
+				config.Instance.EmbeddingModel)
+		}
+		if err != nil {
+			cmd.PrintErrln(err)
+			return
+		}
+		fmt.Printf("%d cached embeddings deleted\n", deleted)
+	},
+}
+
+// initPersistence loads the configuration and connects to the database, for the commands that need it
+func initPersistence() error {
+	if err := config.Init(); err != nil {
+		return err
+	}
+	return connection.InitConnection(config.Instance.DatabaseURL, logger.Warn)
+}
+
+func init() {
+	RootCmd.AddCommand(cache)
+	cache.AddCommand(cachePrune)
+	cachePrune.Flags().StringVarP(&providerParam, "provider", "r", "", "The provider to prune")
+	cachePrune.Flags().StringVarP(&modelParam, "model", "m", "", "The model to prune")
+}
@@ -0,0 +1,55 @@
+/*
+ * Copyright (C) 2026 Simone Pezzano
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/theirish81/meta/internal/persistence/services"
+)
+
+var index = &cobra.Command{
+	Use:   "index",
+	Short: "Manage the vector indexes",
+}
+
+var indexRebuild = &cobra.Command{
+	Use:   "rebuild",
+	Short: "Rebuild the vector indexes with the configured settings, without blocking writes",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := initPersistence(); err != nil {
+			cmd.PrintErrln(err)
+			return
+		}
+		err := services.NewVectorIndexService().Rebuild(context.Background(), func(table string) {
+			fmt.Printf("rebuilding the index of %s\n", table)
+		})
+		if err != nil {
+			cmd.PrintErrln(err)
+			return
+		}
+		fmt.Println("done")
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(index)
+	index.AddCommand(indexRebuild)
+}
@@ -0,0 +1,100 @@
+/*
+ * Copyright (C) 2026 Simone Pezzano
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/theirish81/meta/internal/config"
+	"github.com/theirish81/meta/internal/persistence/services"
+)
+
+var (
+	reindexSubjectParam   string
+	reindexMemoryParam    string
+	reindexBatchSizeParam int
+	reindexDryRunParam    bool
+	reindexForceParam     bool
+)
+
+var reindex = &cobra.Command{
+	Use:   "reindex",
+	Short: "Recompute knowledge and recipe embeddings with the configured model",
+	Run: func(cmd *cobra.Command, args []string) {
+		if reindexBatchSizeParam < 1 {
+			cmd.PrintErrln("The batch size must be at least 1")
+			return
+		}
+		if err := initPersistence(); err != nil {
+			cmd.PrintErrln(err)
+			return
+		}
+		if err := services.InitEmbedding(); err != nil {
+			cmd.PrintErrln(err)
+			return
+		}
+		reindexService := services.NewReindexService()
+		if err := reindexService.InitTables(context.Background()); err != nil {
+			cmd.PrintErrln(err)
+			return
+		}
+		opts := services.ReindexOptions{
+			Subject:   reindexSubjectParam,
+			Memory:    reindexMemoryParam,
+			BatchSize: reindexBatchSizeParam,
+			Force:     reindexForceParam,
+		}
+		plans, err := reindexService.Plan(context.Background(), opts)
+		if err != nil {
+			cmd.PrintErrln(err)
+			return
+		}
+		fmt.Printf("Reindexing with %s/%s (%d dimensions)\n", config.Instance.EmbeddingService,
+			config.Instance.EmbeddingModel, services.Services.EmbeddingDimensions)
+		for _, plan := range plans {
+			fmt.Printf("%s: %d rows to reindex, %d already staged", plan.Table, plan.Rows, plan.Staged)
+			if plan.DimensionChange() {
+				fmt.Printf(", column resized from %d dimensions", plan.StoredDimensions)
+			}
+			fmt.Println()
+		}
+		if reindexDryRunParam {
+			return
+		}
+		err = reindexService.Run(context.Background(), opts, func(plan services.ReindexPlan, done int64) {
+			fmt.Printf("%s: %d/%d rows embedded\n", plan.Table, done, plan.Rows)
+		})
+		if err != nil {
+			cmd.PrintErrln(err)
+			return
+		}
+		fmt.Println("Reindex complete")
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(reindex)
+	reindex.Flags().StringVarP(&reindexSubjectParam, "subject", "s", "", "Only reindex the data of this subject")
+	reindex.Flags().StringVarP(&reindexMemoryParam, "memory", "m", "", "Only reindex this memory")
+	reindex.Flags().IntVarP(&reindexBatchSizeParam, "batch-size", "b", 100, "The number of rows embedded at once")
+	reindex.Flags().BoolVar(&reindexDryRunParam, "dry-run", false, "Report what would be reindexed and exit")
+	reindex.Flags().BoolVar(&reindexForceParam, "force", false,
+		"Also reindex the rows already embedded by the configured model")
+}
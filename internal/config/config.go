@@ -20,19 +20,71 @@ package config
 import (
 	"errors"
 	"io/fs"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/spf13/viper"
 )
 
 type Config struct {
-	KbDistanceThreshold   float64 `mapstructure:"KB_DISTANCE_THRESHOLD" validate:"required,numeric,min=0,max=1"`
-	MetaDistanceThreshold float64 `mapstructure:"META_DISTANCE_THRESHOLD" validate:"required,numeric,min=0,max=1"`
-	DatabaseURL           string  `mapstructure:"DATABASE_URL" validate:"required"`
-	EmbeddingModel        string  `mapstructure:"EMBEDDING_MODEL" validate:"required"`
-	OllamaBaseURL         string  `mapstructure:"OLLAMA_BASE_URL" validate:"url"`
-	EmbeddingService      string  `mapstructure:"EMBEDDING_SERVICE" validate:"required,oneof=ollama gemini"`
-	GeminiProjectID       string  `mapstructure:"GEMINI_PROJECT_ID"`
+	KbDistanceThreshold       float64       `mapstructure:"KB_DISTANCE_THRESHOLD" validate:"required,numeric,min=0,max=1"`
+	KbHybridWeight            float64       `mapstructure:"KB_HYBRID_WEIGHT" validate:"min=0,max=1"`
+	KbSearchMaxLimit          int           `mapstructure:"KB_SEARCH_MAX_LIMIT" validate:"min=1"`
+	KbMMRLambda               float64       `mapstructure:"KB_MMR_LAMBDA" validate:"min=0,max=1"`
+	KbMaxPerDocument          int           `mapstructure:"KB_MAX_PER_DOCUMENT" validate:"min=0"`
+	KbMaxContextWindow        int           `mapstructure:"KB_MAX_CONTEXT_WINDOW" validate:"min=0"`
+	RecipeSearchMaxLimit      int           `mapstructure:"RECIPE_SEARCH_MAX_LIMIT" validate:"min=1"`
+	SearchMaxOffset           int           `mapstructure:"SEARCH_MAX_OFFSET" validate:"min=0"`
+	UploadMaxMB               int           `mapstructure:"UPLOAD_MAX_MB" validate:"min=1"`
+	ChunkStrategy             string        `mapstructure:"CHUNK_STRATEGY" validate:"required,oneof=auto recursive markdown sentence paragraph semantic code"`
+	ChunkSize                 int           `mapstructure:"CHUNK_SIZE" validate:"min=1"`
+	ChunkOverlap              int           `mapstructure:"CHUNK_OVERLAP" validate:"min=0,ltfield=ChunkSize"`
+	ChunkUnit                 string        `mapstructure:"CHUNK_UNIT" validate:"required,oneof=characters tokens"`
+	ChunkEncoding             string        `mapstructure:"CHUNK_ENCODING" validate:"required,oneof=cl100k_base p50k_base r50k_base"`
+	ChunkBreakpointPercentile float64       `mapstructure:"CHUNK_BREAKPOINT_PERCENTILE" validate:"min=0,max=100"`
+	MetaDistanceThreshold     float64       `mapstructure:"META_DISTANCE_THRESHOLD" validate:"required,numeric,min=0,max=1"`
+	DatabaseURL               string        `mapstructure:"DATABASE_URL" validate:"required"`
+	EmbeddingModel            string        `mapstructure:"EMBEDDING_MODEL" validate:"required"`
+	OllamaBaseURL             string        `mapstructure:"OLLAMA_BASE_URL" validate:"required_if=EmbeddingService ollama,omitempty,url"`
+	OllamaBatchSize           int           `mapstructure:"OLLAMA_BATCH_SIZE" validate:"min=1"`
+	OllamaConcurrency         int           `mapstructure:"OLLAMA_CONCURRENCY" validate:"min=1"`
+	EmbeddingService          string        `mapstructure:"EMBEDDING_SERVICE" validate:"required,oneof=ollama gemini openai"`
+	EmbeddingDimensions       int           `mapstructure:"EMBEDDING_DIMENSIONS" validate:"min=0,max=16000"`
+	EmbeddingQueryTemplate    string        `mapstructure:"EMBEDDING_QUERY_TEMPLATE" validate:"omitempty,contains={query}"`
+	EmbeddingTimeout          time.Duration `mapstructure:"EMBEDDING_TIMEOUT" validate:"required"`
+	EmbeddingMaxRetries       int           `mapstructure:"EMBEDDING_MAX_RETRIES" validate:"min=0"`
+	EmbeddingRetryDelay       time.Duration `mapstructure:"EMBEDDING_RETRY_DELAY" validate:"required"`
+	EmbeddingRetryMaxDelay    time.Duration `mapstructure:"EMBEDDING_RETRY_MAX_DELAY" validate:"required,gtefield=EmbeddingRetryDelay"`
+	EmbeddingBreakerThreshold int           `mapstructure:"EMBEDDING_BREAKER_THRESHOLD" validate:"min=1"`
+	EmbeddingBreakerCooldown  time.Duration `mapstructure:"EMBEDDING_BREAKER_COOLDOWN" validate:"required"`
+	GeminiProjectID           string        `mapstructure:"GEMINI_PROJECT_ID"`
+	OpenAIBaseURL             string        `mapstructure:"OPENAI_BASE_URL" validate:"required_if=EmbeddingService openai,omitempty,url"`
+	OpenAIAPIKey              string        `mapstructure:"OPENAI_API_KEY"`
+	OpenAIBatchSize           int           `mapstructure:"OPENAI_BATCH_SIZE" validate:"min=1"`
+	VectorIndex               string        `mapstructure:"VECTOR_INDEX" validate:"required,oneof=hnsw ivfflat none"`
+	HnswM                     int           `mapstructure:"HNSW_M" validate:"min=2,max=100"`
+	HnswEfConstruction        int           `mapstructure:"HNSW_EF_CONSTRUCTION" validate:"min=4,max=1000"`
+	HnswEfSearch              int           `mapstructure:"HNSW_EF_SEARCH" validate:"min=1,max=1000"`
+	HnswIterativeScan         string        `mapstructure:"HNSW_ITERATIVE_SCAN" validate:"required,oneof=off strict_order relaxed_order"`
+	IvfflatLists              int           `mapstructure:"IVFFLAT_LISTS" validate:"min=1,max=32768"`
+	IvfflatProbes             int           `mapstructure:"IVFFLAT_PROBES" validate:"min=1,max=32768"`
+	RerankService             string        `mapstructure:"RERANK_SERVICE" validate:"required,oneof=none ollama tei openai"`
+	RerankBaseURL             string        `mapstructure:"RERANK_BASE_URL" validate:"required_unless=RerankService none,omitempty,url"`
+	RerankAPIKey              string        `mapstructure:"RERANK_API_KEY"`
+	RerankModel               string        `mapstructure:"RERANK_MODEL" validate:"required_if=RerankService ollama"`
+	RerankTimeout             time.Duration `mapstructure:"RERANK_TIMEOUT" validate:"required"`
+	RerankMinScore            float64       `mapstructure:"RERANK_MIN_SCORE"`
+	RerankBreakerThreshold    int           `mapstructure:"RERANK_BREAKER_THRESHOLD" validate:"min=1"`
+	RerankBreakerCooldown     time.Duration `mapstructure:"RERANK_BREAKER_COOLDOWN" validate:"required"`
+	KbRerankCandidates        int           `mapstructure:"KB_RERANK_CANDIDATES" validate:"gtefield=KbRerankTopK"`
+	KbRerankTopK              int           `mapstructure:"KB_RERANK_TOP_K" validate:"min=1"`
+	RecipeRerankCandidates    int           `mapstructure:"RECIPE_RERANK_CANDIDATES" validate:"gtefield=RecipeRerankTopK"`
+	RecipeRerankTopK          int           `mapstructure:"RECIPE_RERANK_TOP_K" validate:"min=1"`
+	JobWorkers                int           `mapstructure:"JOB_WORKERS" validate:"min=0"`
+	JobPollInterval           time.Duration `mapstructure:"JOB_POLL_INTERVAL" validate:"required"`
+	JobLease                  time.Duration `mapstructure:"JOB_LEASE" validate:"required"`
+	JobMaxAttempts            int           `mapstructure:"JOB_MAX_ATTEMPTS" validate:"min=1"`
+	JobRetention              time.Duration `mapstructure:"JOB_RETENTION" validate:"required"`
 }
 
 var Instance Config
@@ -42,9 +94,60 @@ func Init() error {
 	viper.SetConfigType("env")
 	viper.SetDefault("EMBEDDING_MODEL", "")
 	viper.SetDefault("KB_DISTANCE_THRESHOLD", "")
+	viper.SetDefault("KB_HYBRID_WEIGHT", 0.5)
+	viper.SetDefault("KB_SEARCH_MAX_LIMIT", 100)
+	viper.SetDefault("KB_MMR_LAMBDA", 1)
+	viper.SetDefault("KB_MAX_PER_DOCUMENT", 0)
+	viper.SetDefault("KB_MAX_CONTEXT_WINDOW", 5)
+	viper.SetDefault("RECIPE_SEARCH_MAX_LIMIT", 50)
+	viper.SetDefault("SEARCH_MAX_OFFSET", 1000)
+	viper.SetDefault("UPLOAD_MAX_MB", 32)
+	viper.SetDefault("CHUNK_STRATEGY", "auto")
+	viper.SetDefault("CHUNK_SIZE", 500)
+	viper.SetDefault("CHUNK_OVERLAP", 50)
+	viper.SetDefault("CHUNK_UNIT", "characters")
+	viper.SetDefault("CHUNK_ENCODING", "cl100k_base")
+	viper.SetDefault("CHUNK_BREAKPOINT_PERCENTILE", 95)
 	viper.SetDefault("META_DISTANCE_THRESHOLD", "")
 	viper.SetDefault("DATABASE_URL", "")
 	viper.SetDefault("OLLAMA_BASE_URL", "")
+	viper.SetDefault("OLLAMA_BATCH_SIZE", 32)
+	viper.SetDefault("OLLAMA_CONCURRENCY", 4)
+	viper.SetDefault("EMBEDDING_DIMENSIONS", 0)
+	viper.SetDefault("EMBEDDING_QUERY_TEMPLATE", "")
+	viper.SetDefault("EMBEDDING_TIMEOUT", "60s")
+	viper.SetDefault("EMBEDDING_MAX_RETRIES", 4)
+	viper.SetDefault("EMBEDDING_RETRY_DELAY", "500ms")
+	viper.SetDefault("EMBEDDING_RETRY_MAX_DELAY", "10s")
+	viper.SetDefault("EMBEDDING_BREAKER_THRESHOLD", 5)
+	viper.SetDefault("EMBEDDING_BREAKER_COOLDOWN", "30s")
+	viper.SetDefault("OPENAI_BASE_URL", "")
+	viper.SetDefault("OPENAI_API_KEY", "")
+	viper.SetDefault("OPENAI_BATCH_SIZE", 64)
+	viper.SetDefault("VECTOR_INDEX", "hnsw")
+	viper.SetDefault("HNSW_M", 16)
+	viper.SetDefault("HNSW_EF_CONSTRUCTION", 64)
+	viper.SetDefault("HNSW_EF_SEARCH", 40)
+	viper.SetDefault("HNSW_ITERATIVE_SCAN", "relaxed_order")
+	viper.SetDefault("IVFFLAT_LISTS", 100)
+	viper.SetDefault("IVFFLAT_PROBES", 1)
+	viper.SetDefault("RERANK_SERVICE", "none")
+	viper.SetDefault("RERANK_BASE_URL", "")
+	viper.SetDefault("RERANK_API_KEY", "")
+	viper.SetDefault("RERANK_MODEL", "")
+	viper.SetDefault("RERANK_TIMEOUT", "30s")
+	viper.SetDefault("RERANK_MIN_SCORE", 0)
+	viper.SetDefault("RERANK_BREAKER_THRESHOLD", 5)
+	viper.SetDefault("RERANK_BREAKER_COOLDOWN", "30s")
+	viper.SetDefault("KB_RERANK_CANDIDATES", 50)
+	viper.SetDefault("KB_RERANK_TOP_K", 15)
+	viper.SetDefault("RECIPE_RERANK_CANDIDATES", 20)
+	viper.SetDefault("RECIPE_RERANK_TOP_K", 5)
+	viper.SetDefault("JOB_WORKERS", 2)
+	viper.SetDefault("JOB_POLL_INTERVAL", "2s")
+	viper.SetDefault("JOB_LEASE", "2m")
+	viper.SetDefault("JOB_MAX_ATTEMPTS", 3)
+	viper.SetDefault("JOB_RETENTION", "168h")
 	viper.AutomaticEnv()
 	if err := viper.ReadInConfig(); err != nil {
 		var configFileNotFoundError *fs.PathError
@@ -52,6 +155,11 @@ func Init() error {
 			return err
 		}
 	}
+	// Ollama grades the candidates with one generation each, so fewer of them fit within RERANK_TIMEOUT
+	if viper.GetString("RERANK_SERVICE") == "ollama" {
+		viper.SetDefault("KB_RERANK_CANDIDATES", 20)
+		viper.SetDefault("RECIPE_RERANK_CANDIDATES", 10)
+	}
 	if err := viper.Unmarshal(&Instance); err != nil {
 		return err
 	}
@@ -4,6 +4,8 @@
 package dto
 
 import (
+	"time"
+
 	openapi_types "github.com/oapi-codegen/runtime/types"
 )
 
@@ -11,6 +13,30 @@ const (
 	BearerAuthScopes = "bearerAuth.Scopes"
 )
 
+// Defines values for ChunkingSettingsEncoding.
+const (
+	Cl100kBase ChunkingSettingsEncoding = "cl100k_base"
+	P50kBase   ChunkingSettingsEncoding = "p50k_base"
+	R50kBase   ChunkingSettingsEncoding = "r50k_base"
+)
+
+// Defines values for ChunkingSettingsStrategy.
+const (
+	Auto      ChunkingSettingsStrategy = "auto"
+	Code      ChunkingSettingsStrategy = "code"
+	Markdown  ChunkingSettingsStrategy = "markdown"
+	Paragraph ChunkingSettingsStrategy = "paragraph"
+	Recursive ChunkingSettingsStrategy = "recursive"
+	Semantic  ChunkingSettingsStrategy = "semantic"
+	Sentence  ChunkingSettingsStrategy = "sentence"
+)
+
+// Defines values for ChunkingSettingsUnit.
+const (
+	Characters ChunkingSettingsUnit = "characters"
+	Tokens     ChunkingSettingsUnit = "tokens"
+)
+
 // Defines values for DataObjectContentType.
 const (
 	Applicationjson DataObjectContentType = "application/json"
@@ -18,6 +44,58 @@ const (
 	Textplain       DataObjectContentType = "text/plain"
 )
 
+// Defines values for IngestionJobState.
+const (
+	Failed    IngestionJobState = "failed"
+	Queued    IngestionJobState = "queued"
+	Running   IngestionJobState = "running"
+	Succeeded IngestionJobState = "succeeded"
+)
+
+// Defines values for SearchKbParamsMode.
+const (
+	Hybrid  SearchKbParamsMode = "hybrid"
+	Keyword SearchKbParamsMode = "keyword"
+	Vector  SearchKbParamsMode = "vector"
+)
+
+// ChunkContext the chunk merged with its neighbours, when a context window is requested
+type ChunkContext struct {
+	Chunk        string `json:"chunk"`
+	EndOrdinal   int    `json:"end_ordinal"`
+	StartOrdinal int    `json:"start_ordinal"`
+}
+
+// ChunkingSettings how documents are split into chunks. Unset fields fall back to the settings of the memory slot, then to the server defaults
+type ChunkingSettings struct {
+	// BreakpointPercentile semantic chunking cuts where the distance between neighbouring sentences is above this percentile of all such distances. Higher values give fewer, larger chunks
+	BreakpointPercentile *float64 `json:"breakpoint_percentile,omitempty"`
+
+	// Encoding the tokenizer measuring tokens
+	Encoding *ChunkingSettingsEncoding `json:"encoding,omitempty"`
+
+	// Overlap how much of the end of a chunk is repeated at the start of the next, in the unit. Semantic chunks do not overlap
+	Overlap *int `json:"overlap,omitempty"`
+
+	// Size the maximum size of a chunk, in the unit
+	Size *int `json:"size,omitempty"`
+
+	// Strategy `auto` splits markdown documents by heading and everything else recursively, `recursive` by paragraphs, lines and words until chunks fit, `markdown` by heading, `sentence` and `paragraph` pack whole sentences or paragraphs, `semantic` cuts between sentences where embedding similarity drops, `code` by function, type and class definitions. `auto` picks `code` for source files
+	Strategy *ChunkingSettingsStrategy `json:"strategy,omitempty"`
+
+	// Unit how size and overlap are measured
+	Unit *ChunkingSettingsUnit `json:"unit,omitempty"`
+}
+
+// ChunkingSettingsEncoding the tokenizer measuring tokens
+type ChunkingSettingsEncoding string
+
+// ChunkingSettingsStrategy `auto` splits markdown documents by heading and everything else recursively, `recursive` by paragraphs, lines and words until chunks fit, `markdown` by heading, `sentence` and `paragraph` pack whole sentences or paragraphs, `semantic` cuts between sentences where embedding similarity drops, `code` by function, type and class definitions. `auto` picks `code` for source files
+type ChunkingSettingsStrategy string
+
+// ChunkingSettingsUnit how size and overlap are measured
+type ChunkingSettingsUnit string
+
 // DataObject defines model for dataObject.
 type DataObject struct {
 	Content     string                 `json:"content"`
@@ -30,20 +108,147 @@ type DataObjectContentType string
 
 // Document defines model for document.
 type Document struct {
-	Content string   `json:"content"`
-	Tags    []string `json:"tags"`
+	// Chunking how documents are split into chunks. Unset fields fall back to the settings of the memory slot, then to the server defaults
+	Chunking *ChunkingSettings `json:"chunking,omitempty"`
+	Content  string            `json:"content"`
+	Tags     []string          `json:"tags"`
+}
+
+// DocumentContent defines model for document_content.
+type DocumentContent struct {
+	// Content the text of the document within the range
+	Content  string            `json:"content"`
+	Document KnowledgeDocument `json:"document"`
+
+	// EndByte the byte the content ends before
+	EndByte int `json:"end_byte"`
+	EndLine int `json:"end_line"`
+
+	// FileName the name of the file the document was uploaded as
+	FileName *string `json:"file_name,omitempty"`
+
+	// MediaType the media type of the file the document was uploaded as, detected from its name and content
+	MediaType *string `json:"media_type,omitempty"`
+	StartByte int     `json:"start_byte"`
+	StartLine int     `json:"start_line"`
+
+	// TotalBytes the size of the whole text
+	TotalBytes int `json:"total_bytes"`
+	TotalLines int `json:"total_lines"`
+}
+
+// DocumentUpload defines model for document_upload.
+type DocumentUpload struct {
+	// Chunking JSON encoded chunking settings, overriding the ones of the memory slot for this document
+	Chunking *string            `json:"chunking,omitempty"`
+	File     openapi_types.File `json:"file"`
+	Tags     *[]string          `json:"tags,omitempty"`
+}
+
+// EmbeddingModelUsage defines model for embedding_model_usage.
+type EmbeddingModelUsage struct {
+	Count int64 `json:"count"`
+
+	// Current whether this is the configured model, the only one searches compare against
+	Current  bool   `json:"current"`
+	Model    string `json:"model"`
+	Provider string `json:"provider"`
+}
+
+// EmbeddingModels defines model for embedding_models.
+type EmbeddingModels map[string][]EmbeddingModelUsage
+
+// IngestionJob defines model for ingestion_job.
+type IngestionJob struct {
+	Attempts       int `json:"attempts"`
+	ChunksEmbedded int `json:"chunks_embedded"`
+
+	// ChunksToEmbed the chunks of the document that need embedding. Chunks whose content did not change are not embedded again
+	ChunksToEmbed int       `json:"chunks_to_embed"`
+	CreatedAt     time.Time `json:"created_at"`
+	Document      string    `json:"document"`
+
+	// DocumentVersion the version of the document once recorded
+	DocumentVersion *int `json:"document_version,omitempty"`
+
+	// Error the error of the last failed attempt
+	Error      *string            `json:"error,omitempty"`
+	FinishedAt *time.Time         `json:"finished_at,omitempty"`
+	Id         openapi_types.UUID `json:"id"`
+	Memory     string             `json:"memory"`
+	StartedAt  *time.Time         `json:"started_at,omitempty"`
+
+	// State a failed attempt is queued again, unless it cannot succeed or no attempts are left
+	State IngestionJobState `json:"state"`
 }
 
+// IngestionJobState a failed attempt is queued again, unless it cannot succeed or no attempts are left
+type IngestionJobState string
+
 // KnowledgeChunk defines model for knowledge_chunk.
 type KnowledgeChunk struct {
-	Chunk    string   `json:"chunk"`
+	Chunk string `json:"chunk"`
+
+	// Context the chunk merged with its neighbours, when a context window is requested
+	Context *ChunkContext `json:"context,omitempty"`
+
+	// Distance the cosine distance to the query. Absent in keyword searches
+	Distance *float64 `json:"distance,omitempty"`
 	Document string   `json:"document"`
-	Tags     []string `json:"tags"`
+
+	// EndLine the last line of a chunk of source code in its document
+	EndLine *int `json:"end_line,omitempty"`
+
+	// EndOffset the byte offset where the chunk ends in the text of its document, exclusive, to be read back as the end_byte of a document range
+	EndOffset *int `json:"end_offset,omitempty"`
+
+	// Headings the headings the chunk falls under, from the outermost
+	Headings *[]string `json:"headings,omitempty"`
+
+	// Id identifies the chunk, to cite it and fetch it again. It stays the same as long as the text of the chunk does, across submissions of its document
+	Id     openapi_types.UUID `json:"id"`
+	Memory string             `json:"memory"`
+
+	// Ordinal the position of the chunk in its document, starting from 0. Absent for chunks recorded before positions were tracked
+	Ordinal *int `json:"ordinal,omitempty"`
+
+	// StartLine the first line of a chunk of source code in its document, starting from 1
+	StartLine *int `json:"start_line,omitempty"`
+
+	// StartOffset the byte offset where the chunk starts in the text of its document, to be read back as the start_byte of a document range
+	StartOffset *int `json:"start_offset,omitempty"`
+
+	// Symbol the function, type or class a chunk of source code defines
+	Symbol *string  `json:"symbol,omitempty"`
+	Tags   []string `json:"tags"`
 }
 
 // KnowledgeChunks defines model for knowledge_chunks.
 type KnowledgeChunks = []KnowledgeChunk
 
+// KnowledgeDocument defines model for knowledge_document.
+type KnowledgeDocument struct {
+	ChunkCount int `json:"chunk_count"`
+
+	// Chunking how documents are split into chunks. Unset fields fall back to the settings of the memory slot, then to the server defaults
+	Chunking ChunkingSettings `json:"chunking"`
+
+	// ContentHash the SHA-256 of the extracted text. Empty for documents recorded before it was tracked
+	ContentHash string             `json:"content_hash"`
+	CreatedAt   time.Time          `json:"created_at"`
+	Id          openapi_types.UUID `json:"id"`
+	Memory      string             `json:"memory"`
+	Name        string             `json:"name"`
+
+	// Size the size of the extracted text, in bytes
+	Size      int64     `json:"size"`
+	Tags      []string  `json:"tags"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Version grows every time the document is submitted with changes
+	Version int `json:"version"`
+}
+
 // Memories defines model for memories.
 type Memories map[string]Memory
 
@@ -52,13 +257,22 @@ type Memory struct {
 	AvailableTags []string `json:"available_tags"`
 }
 
+// MemorySettings defines model for memory_settings.
+type MemorySettings struct {
+	// Chunking how documents are split into chunks. Unset fields fall back to the settings of the memory slot, then to the server defaults
+	Chunking *ChunkingSettings `json:"chunking,omitempty"`
+}
+
 // Recipe defines model for recipe.
 type Recipe struct {
-	Content     string             `json:"content"`
-	Description string             `json:"description"`
-	Id          openapi_types.UUID `json:"id"`
-	Name        string             `json:"name"`
-	Tags        []string           `json:"tags"`
+	Content     string `json:"content"`
+	Description string `json:"description"`
+
+	// Distance the cosine distance to the query, in search results
+	Distance *float64           `json:"distance,omitempty"`
+	Id       openapi_types.UUID `json:"id"`
+	Name     string             `json:"name"`
+	Tags     []string           `json:"tags"`
 }
 
 // RecipeRequest defines model for recipe_request.
@@ -72,10 +286,57 @@ type RecipeRequest struct {
 // Recipes defines model for recipes.
 type Recipes = []Recipe
 
+// GetKbChunksParams defines parameters for GetKbChunks.
+type GetKbChunksParams struct {
+	Id []openapi_types.UUID `form:"id" json:"id"`
+}
+
 // SearchKbParams defines parameters for SearchKb.
 type SearchKbParams struct {
 	Tag *[]string `form:"tag,omitempty" json:"tag,omitempty"`
 	Q   string    `form:"q" json:"q"`
+
+	// Mode how chunks are matched. `vector` by semantic similarity, `keyword` by full-text match of every word of the query, `hybrid` by both
+	Mode *SearchKbParamsMode `form:"mode,omitempty" json:"mode,omitempty"`
+
+	// Limit the maximum number of results. Values above the server cap are lowered to the cap
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Offset the number of results to skip, to paginate
+	Offset *int `form:"offset,omitempty" json:"offset,omitempty"`
+
+	// MaxDistance the cosine distance beyond which results are discarded, overriding the configured threshold
+	MaxDistance *float64 `form:"max_distance,omitempty" json:"max_distance,omitempty"`
+
+	// MmrLambda diversifies the results by maximal marginal relevance. `1` ranks by relevance only, lower values favour chunks unlike the ones already returned. Relevance is one minus the distance of vector and hybrid results, and the position in the ranking of keyword and reranked results
+	MmrLambda *float64 `form:"mmr_lambda,omitempty" json:"mmr_lambda,omitempty"`
+
+	// MaxPerDocument the maximum number of chunks of the same document. `0` means no cap
+	MaxPerDocument *int `form:"max_per_document,omitempty" json:"max_per_document,omitempty"`
+
+	// ContextWindow the number of neighbouring chunks, on each side, merged with each result into its context
+	ContextWindow *int `form:"context_window,omitempty" json:"context_window,omitempty"`
+}
+
+// SearchKbParamsMode defines parameters for SearchKb.
+type SearchKbParamsMode string
+
+// GetDocumentParams defines parameters for GetDocument.
+type GetDocumentParams struct {
+	// StartByte the first byte of the text to return, from 0
+	StartByte *int `form:"start_byte,omitempty" json:"start_byte,omitempty"`
+
+	// EndByte the byte the text to return ends before. Bounds falling within a character are widened to include it
+	EndByte *int `form:"end_byte,omitempty" json:"end_byte,omitempty"`
+
+	// StartLine the first line of the text to return, from 1. Lines cannot be combined with bytes
+	StartLine *int `form:"start_line,omitempty" json:"start_line,omitempty"`
+
+	// EndLine the last line of the text to return
+	EndLine *int `form:"end_line,omitempty" json:"end_line,omitempty"`
+
+	// Original returns the file the document was uploaded as, or its text when submitted as JSON. Ranges do not apply
+	Original *bool `form:"original,omitempty" json:"original,omitempty"`
 }
 
 // DeleteObjectByNameParams defines parameters for DeleteObjectByName.
@@ -92,11 +353,26 @@ type GetObjectByNameParams struct {
 type SearchRecipesParams struct {
 	Tag *[]string `form:"tag,omitempty" json:"tag,omitempty"`
 	Q   *string   `form:"q,omitempty" json:"q,omitempty"`
+
+	// Limit the maximum number of results. Values above the server cap are lowered to the cap
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Offset the number of results to skip, to paginate
+	Offset *int `form:"offset,omitempty" json:"offset,omitempty"`
+
+	// MaxDistance the cosine distance beyond which results are discarded, overriding the configured threshold
+	MaxDistance *float64 `form:"max_distance,omitempty" json:"max_distance,omitempty"`
 }
 
 // SubmitDocumentJSONRequestBody defines body for SubmitDocument for application/json ContentType.
 type SubmitDocumentJSONRequestBody = Document
 
+// SubmitDocumentMultipartRequestBody defines body for SubmitDocument for multipart/form-data ContentType.
+type SubmitDocumentMultipartRequestBody = DocumentUpload
+
+// UpdateKbSettingsJSONRequestBody defines body for UpdateKbSettings for application/json ContentType.
+type UpdateKbSettingsJSONRequestBody = MemorySettings
+
 // CreateObjectJSONRequestBody defines body for CreateObject for application/json ContentType.
 type CreateObjectJSONRequestBody = DataObject
 
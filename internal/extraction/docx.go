@@ -0,0 +1,117 @@
+/*
+ * Copyright (C) 2026 Simone Pezzano
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package extraction
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const wordNamespace = "http://schemas.openxmlformats.org/wordprocessingml/2006/main"
+
+// extractDOCX returns the paragraphs of the main document part, turning heading styles into markdown headings
+func extractDOCX(data []byte) (string, error) {
+	archive, err := openZip(data)
+	if err != nil {
+		return "", err
+	}
+	document, err := archive.readFile("word/document.xml")
+	if err != nil {
+		return "", err
+	}
+	decoder := xml.NewDecoder(bytes.NewReader(document))
+	writer := &textWriter{}
+	var paragraph strings.Builder
+	heading := 0
+	inText := false
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		switch element := token.(type) {
+		case xml.StartElement:
+			if element.Name.Space != wordNamespace {
+				continue
+			}
+			switch element.Name.Local {
+			case "p":
+				paragraph.Reset()
+				heading = 0
+			case "pStyle":
+				heading = headingLevel(attribute(element, "val"))
+			case "t":
+				inText = true
+			case "tab":
+				paragraph.WriteString("\t")
+			case "br", "cr":
+				paragraph.WriteString("\n")
+			}
+		case xml.CharData:
+			if inText {
+				paragraph.Write(element)
+			}
+		case xml.EndElement:
+			if element.Name.Space != wordNamespace {
+				continue
+			}
+			switch element.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				text := strings.TrimSpace(paragraph.String())
+				if text == "" {
+					continue
+				}
+				if heading > 0 {
+					text = strings.Repeat("#", heading) + " " + text
+				}
+				writer.writeWord(text)
+				writer.breakLines(2)
+			}
+		}
+	}
+	return writer.String(), nil
+}
+
+// headingLevel returns the level of the built-in heading styles, such as Heading1 or Title, or 0
+func headingLevel(style string) int {
+	if style == "Title" {
+		return 1
+	}
+	if level, err := strconv.Atoi(strings.TrimPrefix(style, "Heading")); err == nil && level > 0 {
+		return min(level, 6)
+	}
+	return 0
+}
+
+func attribute(element xml.StartElement, name string) string {
+	for _, attr := range element.Attr {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,112 @@
+/*
+ * Copyright (C) 2026 Simone Pezzano
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package extraction
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/theirish81/meta/internal/config"
+)
+
+// docx packs files into an archive, as a word processor would
+func docx(t *testing.T, files map[string]string) []byte {
+	var buffer bytes.Buffer
+	archive := zip.NewWriter(&buffer)
+	for name, content := range files {
+		writer, err := archive.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = writer.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buffer.Bytes()
+}
+
+// wordDocument wraps paragraphs in the main document part
+func wordDocument(body string) map[string]string {
+	return map[string]string{
+		"[Content_Types].xml": `<?xml version="1.0"?><Types/>`,
+		"word/document.xml": `<?xml version="1.0"?><w:document xmlns:w="` + wordNamespace + `" ` +
+			`xmlns:v="urn:other"><w:body>` + body + `</w:body></w:document>`,
+	}
+}
+
+func TestExtractDOCX(t *testing.T) {
+	previous := config.Instance
+	defer func() {
+		config.Instance = previous
+	}()
+	config.Instance.UploadMaxMB = 1
+	tests := []struct {
+		name    string
+		files   map[string]string
+		want    string
+		wantErr bool
+	}{
+		{name: "paragraphs", files: wordDocument(`<w:p><w:r><w:t>Hello</w:t></w:r><w:r><w:t xml:space="preserve">` +
+			` world</w:t></w:r></w:p><w:p><w:r><w:t>Second</w:t></w:r></w:p>`),
+			want: "Hello world\n\nSecond\n\n"},
+		{name: "headings", files: wordDocument(`<w:p><w:pPr><w:pStyle w:val="Title"/></w:pPr><w:r><w:t>Report</w:t>` +
+			`</w:r></w:p><w:p><w:pPr><w:pStyle w:val="Heading2"/></w:pPr><w:r><w:t>Scope</w:t></w:r></w:p>` +
+			`<w:p><w:pPr><w:pStyle w:val="Normal"/></w:pPr><w:r><w:t>Text</w:t></w:r></w:p>`),
+			want: "# Report\n\n## Scope\n\nText\n\n"},
+		{name: "tabs and breaks", files: wordDocument(`<w:p><w:r><w:t>a</w:t><w:tab/><w:t>b</w:t><w:br/>` +
+			`<w:t>c</w:t></w:r></w:p>`), want: "a\tb\nc\n\n"},
+		{name: "empty paragraphs and other namespaces skipped", files: wordDocument(`<w:p></w:p>` +
+			`<w:p><w:r><v:t>hidden</v:t><w:t>shown</w:t></w:r></w:p>`), want: "shown\n\n"},
+		{name: "missing document part", files: map[string]string{"word/styles.xml": "<styles/>"},
+			wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := extractDOCX(docx(t, test.files))
+			if test.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestExtractDOCXBudget(t *testing.T) {
+	previous := config.Instance
+	defer func() {
+		config.Instance = previous
+	}()
+	config.Instance.UploadMaxMB = 0
+	data := docx(t, wordDocument(`<w:p><w:r><w:t>Hello</w:t></w:r></w:p>`))
+	if _, _, err := Extract(data, "report.docx"); !errors.Is(err, ErrUnreadableDocument) {
+		t.Errorf("got error %v, want %v", err, ErrUnreadableDocument)
+	}
+}
@@ -0,0 +1,89 @@
+/*
+ * Copyright (C) 2026 Simone Pezzano
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package extraction
+
+import (
+	"encoding/xml"
+	"errors"
+	"net/url"
+	"path"
+	"strings"
+)
+
+type epubContainer struct {
+	Rootfiles []struct {
+		FullPath string `xml:"full-path,attr"`
+	} `xml:"rootfiles>rootfile"`
+}
+
+type epubPackage struct {
+	Manifest []struct {
+		ID   string `xml:"id,attr"`
+		Href string `xml:"href,attr"`
+	} `xml:"manifest>item"`
+	Spine []struct {
+		IDRef string `xml:"idref,attr"`
+	} `xml:"spine>itemref"`
+}
+
+// extractEPUB returns the text of the chapters, in reading order
+func extractEPUB(data []byte) (string, error) {
+	archive, err := openZip(data)
+	if err != nil {
+		return "", err
+	}
+	content, err := archive.readFile("META-INF/container.xml")
+	if err != nil {
+		return "", err
+	}
+	container := epubContainer{}
+	if err = xml.Unmarshal(content, &container); err != nil {
+		return "", err
+	}
+	if len(container.Rootfiles) == 0 {
+		return "", errors.New("the container declares no package")
+	}
+	packagePath := container.Rootfiles[0].FullPath
+	if content, err = archive.readFile(packagePath); err != nil {
+		return "", err
+	}
+	pkg := epubPackage{}
+	if err = xml.Unmarshal(content, &pkg); err != nil {
+		return "", err
+	}
+	hrefs := make(map[string]string)
+	for _, item := range pkg.Manifest {
+		hrefs[item.ID] = item.Href
+	}
+	chapters := make([]string, 0, len(pkg.Spine))
+	for _, item := range pkg.Spine {
+		href, err := url.PathUnescape(hrefs[item.IDRef])
+		if err != nil || href == "" {
+			continue
+		}
+		if content, err = archive.readFile(path.Join(path.Dir(packagePath), href)); err != nil {
+			return "", err
+		}
+		chapter, err := extractHTML(content)
+		if err != nil {
+			return "", err
+		}
+		chapters = append(chapters, chapter)
+	}
+	return strings.Join(chapters, "\n\n"), nil
+}
@@ -0,0 +1,270 @@
+/*
+ * Copyright (C) 2026 Simone Pezzano
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package extraction
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/theirish81/meta/internal/config"
+)
+
+// Format is the markup of an extracted text, or the language of source code, which drives how the text gets split
+type Format string
+
+const (
+	FormatText     Format = "text"
+	FormatMarkdown Format = "markdown"
+	FormatGo       Format = "go"
+	// FormatCode is source code of any language but Go
+	FormatCode Format = "code"
+)
+
+var (
+	// ErrUnsupportedFormat is returned when the type of a file cannot be detected or has no extractor
+	ErrUnsupportedFormat = errors.New("unsupported document format")
+	// ErrUnreadableDocument is returned when a file of a supported type cannot be parsed, or holds no text
+	ErrUnreadableDocument = errors.New("unreadable document")
+)
+
+type extractor func(data []byte) (string, error)
+
+var extractors = map[string]struct {
+	extract   extractor
+	format    Format
+	mediaType string
+}{
+	"pdf":      {extractPDF, FormatText, "application/pdf"},
+	"docx":     {extractDOCX, FormatMarkdown, "application/vnd.openxmlformats-officedocument.wordprocessingml.document"},
+	"html":     {extractHTML, FormatMarkdown, "text/html; charset=utf-8"},
+	"epub":     {extractEPUB, FormatMarkdown, "application/epub+zip"},
+	"rtf":      {extractRTF, FormatText, "application/rtf"},
+	"markdown": {extractPlainText, FormatMarkdown, "text/markdown; charset=utf-8"},
+	"text":     {extractPlainText, FormatText, "text/plain; charset=utf-8"},
+	"go":       {extractPlainText, FormatGo, "text/plain; charset=utf-8"},
+	"code":     {extractPlainText, FormatCode, "text/plain; charset=utf-8"},
+}
+
+var extensions = map[string]string{
+	".pdf":      "pdf",
+	".docx":     "docx",
+	".html":     "html",
+	".htm":      "html",
+	".xhtml":    "html",
+	".epub":     "epub",
+	".rtf":      "rtf",
+	".md":       "markdown",
+	".markdown": "markdown",
+	".txt":      "text",
+	".go":       "go",
+	".c":        "code",
+	".h":        "code",
+	".cc":       "code",
+	".cpp":      "code",
+	".hpp":      "code",
+	".cs":       "code",
+	".java":     "code",
+	".kt":       "code",
+	".kts":      "code",
+	".scala":    "code",
+	".swift":    "code",
+	".m":        "code",
+	".rs":       "code",
+	".zig":      "code",
+	".js":       "code",
+	".jsx":      "code",
+	".mjs":      "code",
+	".cjs":      "code",
+	".ts":       "code",
+	".tsx":      "code",
+	".svelte":   "code",
+	".vue":      "code",
+	".py":       "code",
+	".rb":       "code",
+	".php":      "code",
+	".pl":       "code",
+	".lua":      "code",
+	".dart":     "code",
+	".ex":       "code",
+	".exs":      "code",
+	".sh":       "code",
+	".bash":     "code",
+	".sql":      "code",
+	".proto":    "code",
+	".tf":       "code",
+}
+
+// FormatOf returns the format of a text document, given its name
+func FormatOf(name string) Format {
+	if kind, ok := extensions[strings.ToLower(filepath.Ext(name))]; ok && extractors[kind].format != FormatText {
+		return extractors[kind].format
+	}
+	return FormatText
+}
+
+// Extract returns the text of a file. The type is detected from the first of the names with a known extension and,
+// failing that, from the content. Any other UTF-8 file, such as source code, is taken as plain text.
+func Extract(data []byte, names ...string) (text string, format Format, err error) {
+	kind := detect(data, names)
+	if kind == "" {
+		return "", "", fmt.Errorf("%w: %s", ErrUnsupportedFormat, http.DetectContentType(data))
+	}
+	// The parsers of binary formats may panic on malformed files
+	defer func() {
+		if e := recover(); e != nil {
+			err = fmt.Errorf("%w: %s file is malformed: %v", ErrUnreadableDocument, kind, e)
+		}
+	}()
+	text, err = extractors[kind].extract(data)
+	if err != nil {
+		return "", "", fmt.Errorf("%w: %s file: %w", ErrUnreadableDocument, kind, err)
+	}
+	text = normalize(text, extractors[kind].format)
+	if strings.TrimSpace(text) == "" {
+		return "", "", fmt.Errorf("%w: the %s file holds no text", ErrUnreadableDocument, kind)
+	}
+	return text, extractors[kind].format, nil
+}
+
+// MediaType returns the media type of a file, detected as Extract does, or application/octet-stream when the type is
+// not supported
+func MediaType(data []byte, names ...string) string {
+	if kind := detect(data, names); kind != "" {
+		return extractors[kind].mediaType
+	}
+	return "application/octet-stream"
+}
+
+// KnownMediaType tells whether a media type is one that MediaType detects
+func KnownMediaType(mediaType string) bool {
+	for _, item := range extractors {
+		if item.mediaType == mediaType {
+			return true
+		}
+	}
+	return false
+}
+
+func detect(data []byte, names []string) string {
+	for _, name := range names {
+		if kind, ok := extensions[strings.ToLower(filepath.Ext(name))]; ok {
+			return kind
+		}
+	}
+	switch {
+	case bytes.HasPrefix(data, []byte("%PDF-")):
+		return "pdf"
+	case bytes.HasPrefix(data, []byte(`{\rtf`)):
+		return "rtf"
+	case bytes.HasPrefix(data, []byte("PK\x03\x04")):
+		return detectZip(data)
+	case strings.HasPrefix(http.DetectContentType(data), "text/html"):
+		return "html"
+	case utf8.Valid(data):
+		return "text"
+	}
+	return ""
+}
+
+// detectZip tells DOCX and EPUB files apart, as both are zip archives
+func detectZip(data []byte) string {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return ""
+	}
+	for _, file := range archive.File {
+		switch file.Name {
+		case "word/document.xml":
+			return "docx"
+		case "META-INF/container.xml":
+			return "epub"
+		}
+	}
+	return ""
+}
+
+func extractPlainText(data []byte) (string, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	if !utf8.Valid(data) {
+		return "", errors.New("the text is not UTF-8 encoded")
+	}
+	return string(data), nil
+}
+
+// normalize unifies line endings, drops the characters Postgres cannot store and squeezes runs of blank lines. Source
+// code is left as it is otherwise, as chunks refer to its line numbers.
+func normalize(text string, format Format) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+	text = strings.ReplaceAll(text, "\x00", "")
+	text = strings.ToValidUTF8(text, "")
+	if format == FormatGo || format == FormatCode {
+		return strings.TrimRight(text, " \t\n")
+	}
+	for strings.Contains(text, "\n\n\n") {
+		text = strings.ReplaceAll(text, "\n\n\n", "\n\n")
+	}
+	return strings.TrimSpace(text)
+}
+
+// zipExpansionFactor is how many times the upload size the files read from an archive can add up to, uncompressed
+const zipExpansionFactor = 10
+
+// zipArchive reads the files of a zip archive within a budget of uncompressed bytes, so that a small archive cannot
+// expand to exhaust the memory
+type zipArchive struct {
+	*zip.Reader
+	budget int64
+}
+
+func openZip(data []byte) (*zipArchive, error) {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+	return &zipArchive{Reader: reader, budget: int64(config.Instance.UploadMaxMB) << 20 * zipExpansionFactor}, nil
+}
+
+// readFile returns the content of a file of the archive
+func (a *zipArchive) readFile(name string) ([]byte, error) {
+	file, err := a.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	var buffer bytes.Buffer
+	read, err := buffer.ReadFrom(io.LimitReader(file, a.budget+1))
+	if err != nil {
+		return nil, err
+	}
+	if read > a.budget {
+		// Extract reports it as ErrUnreadableDocument
+		return nil, fmt.Errorf("the archive expands beyond %dMB", config.Instance.UploadMaxMB*zipExpansionFactor)
+	}
+	a.budget -= read
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,174 @@
+/*
+ * Copyright (C) 2026 Simone Pezzano
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package extraction
+
+import (
+	"bytes"
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// textWriter accumulates text, collapsing whitespace and keeping track of line breaks
+type textWriter struct {
+	buffer bytes.Buffer
+}
+
+// write appends inline text. Runs of whitespace collapse into a single space, unless verbatim.
+func (w *textWriter) write(text string, verbatim bool) {
+	if !verbatim {
+		text = collapseSpaces(text)
+		if strings.HasPrefix(text, " ") && w.endsWithSpace() {
+			text = text[1:]
+		}
+	}
+	w.buffer.WriteString(text)
+}
+
+// writeWord appends text as it is
+func (w *textWriter) writeWord(text string) {
+	w.buffer.WriteString(text)
+}
+
+// endsWithSpace tells whether the text is empty or ends with whitespace
+func (w *textWriter) endsWithSpace() bool {
+	data := w.buffer.Bytes()
+	return len(data) == 0 || strings.ContainsRune(" \t\n", rune(data[len(data)-1]))
+}
+
+// breakLines ends the current line, making sure the text ends with count line breaks
+func (w *textWriter) breakLines(count int) {
+	trimmed := bytes.TrimRight(w.buffer.Bytes(), " \t")
+	w.buffer.Truncate(len(trimmed))
+	if w.buffer.Len() == 0 {
+		return
+	}
+	existing := len(trimmed) - len(bytes.TrimRight(trimmed, "\n"))
+	for ; existing < count; existing++ {
+		w.buffer.WriteString("\n")
+	}
+}
+
+func (w *textWriter) String() string {
+	return w.buffer.String()
+}
+
+// collapseSpaces replaces every run of whitespace with a single space
+func collapseSpaces(text string) string {
+	var builder strings.Builder
+	space := false
+	for _, r := range text {
+		if unicode.IsSpace(r) {
+			space = true
+			continue
+		}
+		if space {
+			builder.WriteRune(' ')
+			space = false
+		}
+		builder.WriteRune(r)
+	}
+	if space {
+		builder.WriteRune(' ')
+	}
+	return builder.String()
+}
+
+// skippedElements hold no readable text
+var skippedElements = map[atom.Atom]bool{
+	atom.Head:     true,
+	atom.Script:   true,
+	atom.Style:    true,
+	atom.Noscript: true,
+	atom.Template: true,
+	atom.Svg:      true,
+	atom.Math:     true,
+	atom.Iframe:   true,
+	atom.Object:   true,
+}
+
+// blockElements start on a line of their own
+var blockElements = map[atom.Atom]bool{
+	atom.Address: true, atom.Article: true, atom.Aside: true, atom.Blockquote: true, atom.Dd: true, atom.Div: true,
+	atom.Dl: true, atom.Dt: true, atom.Figcaption: true, atom.Figure: true, atom.Footer: true, atom.Form: true,
+	atom.Header: true, atom.Hr: true, atom.Main: true, atom.Nav: true, atom.Ol: true, atom.P: true, atom.Section: true,
+	atom.Table: true, atom.Tr: true, atom.Ul: true, atom.Caption: true, atom.Details: true, atom.Summary: true,
+}
+
+var headingLevels = map[atom.Atom]int{
+	atom.H1: 1, atom.H2: 2, atom.H3: 3, atom.H4: 4, atom.H5: 5, atom.H6: 6,
+}
+
+// extractHTML converts an HTML page to markdown-like text: headings, list items and preformatted blocks are kept,
+// everything else becomes paragraphs
+func extractHTML(data []byte) (string, error) {
+	root, err := html.Parse(bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	writer := &textWriter{}
+	writeHTML(writer, root, false)
+	return writer.String(), nil
+}
+
+func writeHTML(w *textWriter, node *html.Node, verbatim bool) {
+	switch node.Type {
+	case html.TextNode:
+		w.write(node.Data, verbatim)
+		return
+	case html.ElementNode:
+		if skippedElements[node.DataAtom] {
+			return
+		}
+	}
+	switch {
+	case headingLevels[node.DataAtom] > 0:
+		w.breakLines(2)
+		w.writeWord(strings.Repeat("#", headingLevels[node.DataAtom]) + " ")
+	case node.DataAtom == atom.Li:
+		w.breakLines(1)
+		w.writeWord("- ")
+	case node.DataAtom == atom.Pre:
+		w.breakLines(2)
+		w.writeWord("```\n")
+		verbatim = true
+	case node.DataAtom == atom.Br:
+		w.breakLines(1)
+	case node.DataAtom == atom.Td, node.DataAtom == atom.Th:
+		if !w.endsWithSpace() {
+			w.writeWord(" ")
+		}
+		w.writeWord("| ")
+	case blockElements[node.DataAtom]:
+		w.breakLines(2)
+	}
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		writeHTML(w, child, verbatim)
+	}
+	switch {
+	case headingLevels[node.DataAtom] > 0, blockElements[node.DataAtom]:
+		w.breakLines(2)
+	case node.DataAtom == atom.Pre:
+		w.breakLines(1)
+		w.writeWord("```\n\n")
+	case node.DataAtom == atom.Li:
+		w.breakLines(1)
+	}
+}
@@ -0,0 +1,52 @@
+/*
+ * Copyright (C) 2026 Simone Pezzano
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package extraction
+
+import "testing"
+
+func TestExtractHTML(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{name: "paragraphs", html: "<p>First  paragraph\nwrapped</p><p>Second</p>",
+			want: "First paragraph wrapped\n\nSecond\n\n"},
+		{name: "headings", html: "<h1>Title</h1><p>Intro</p><h3>Part</h3>text",
+			want: "# Title\n\nIntro\n\n### Part\n\ntext"},
+		{name: "skipped elements", html: "<html><head><title>Tab</title><style>p{}</style></head>" +
+			"<body><script>alert(1)</script><p>Visible</p></body></html>", want: "Visible\n\n"},
+		{name: "lists", html: "<ul><li>one</li><li>two <b>bold</b></li></ul>", want: "- one\n- two bold\n\n"},
+		{name: "preformatted", html: "<p>Code:</p><pre>a  b\n  c</pre>", want: "Code:\n\n```\na  b\n  c\n```\n\n"},
+		{name: "line breaks", html: "one<br>two", want: "one\ntwo"},
+		{name: "tables", html: "<table><tr><th>Name</th><th>Age</th></tr><tr><td>Ann</td><td>30</td></tr></table>",
+			want: "| Name | Age\n\n| Ann | 30\n\n"},
+		{name: "entities", html: "<p>Fish &amp; chips&nbsp;&lt;3</p>", want: "Fish & chips <3\n\n"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := extractHTML([]byte(test.html))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+/*
+ * Copyright (C) 2026 Simone Pezzano
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package extraction
+
+import (
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// rtfSkippedDestinations hold formatting or metadata rather than text
+var rtfSkippedDestinations = map[string]bool{
+	"fonttbl": true, "colortbl": true, "stylesheet": true, "info": true, "pict": true, "object": true,
+	"header": true, "headerl": true, "headerr": true, "headerf": true, "footer": true, "footerl": true,
+	"footerr": true, "footerf": true, "fldinst": true, "listtable": true, "listoverridetable": true,
+	"rsidtbl": true, "generator": true, "themedata": true, "colorschememapping": true, "datastore": true,
+	"latentstyles": true, "xmlnstbl": true, "filetbl": true, "revtbl": true,
+}
+
+type rtfGroup struct {
+	skip bool
+	// unicodeSkip is the number of fallback characters following a \u control word
+	unicodeSkip int
+}
+
+// extractRTF returns the text of an RTF document. Hexadecimal escapes are decoded as Windows-1252, the default
+// code page of RTF writers.
+func extractRTF(data []byte) (string, error) {
+	var out strings.Builder
+	stack := []rtfGroup{{unicodeSkip: 1}}
+	pendingSkip := 0
+	emit := func(text string) {
+		if stack[len(stack)-1].skip {
+			return
+		}
+		if pendingSkip > 0 {
+			pendingSkip--
+			return
+		}
+		out.WriteString(text)
+	}
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		switch c {
+		case '{':
+			stack = append(stack, stack[len(stack)-1])
+			pendingSkip = 0
+		case '}':
+			if len(stack) > 1 {
+				stack = stack[:len(stack)-1]
+			}
+			pendingSkip = 0
+		case '\r', '\n':
+		case '\\':
+			if i+1 >= len(data) {
+				break
+			}
+			i++
+			switch next := data[i]; {
+			case next == '\'':
+				if i+2 < len(data) {
+					if value, err := strconv.ParseUint(string(data[i+1:i+3]), 16, 8); err == nil {
+						emit(string(charmap.Windows1252.DecodeByte(byte(value))))
+					}
+					i += 2
+				}
+			case next == '*':
+				stack[len(stack)-1].skip = true
+			case next == '~':
+				emit(" ")
+			case next == '_':
+				emit("-")
+			case next == '\\', next == '{', next == '}':
+				emit(string(next))
+			case next == '\r', next == '\n':
+				emit("\n")
+			case isLetter(next):
+				start := i
+				for i < len(data) && isLetter(data[i]) {
+					i++
+				}
+				word := string(data[start:i])
+				paramStart := i
+				if i < len(data) && data[i] == '-' {
+					i++
+				}
+				for i < len(data) && data[i] >= '0' && data[i] <= '9' {
+					i++
+				}
+				param, hasParam := 0, i > paramStart
+				if hasParam {
+					param, _ = strconv.Atoi(string(data[paramStart:i]))
+				}
+				// A space delimiting the control word belongs to it
+				if i >= len(data) || data[i] != ' ' {
+					i--
+				}
+				switch {
+				case rtfSkippedDestinations[word]:
+					stack[len(stack)-1].skip = true
+				case word == "par", word == "line", word == "sect", word == "page", word == "row":
+					emit("\n")
+				case word == "tab", word == "cell":
+					emit("\t")
+				case word == "uc" && hasParam:
+					stack[len(stack)-1].unicodeSkip = param
+				case word == "u" && hasParam:
+					if param < 0 {
+						param += 65536
+					}
+					emit(string(rune(param)))
+					pendingSkip = stack[len(stack)-1].unicodeSkip
+				}
+			}
+		default:
+			emit(string(charmap.Windows1252.DecodeByte(c)))
+		}
+	}
+	return out.String(), nil
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
@@ -0,0 +1,51 @@
+/*
+ * Copyright (C) 2026 Simone Pezzano
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package extraction
+
+import "testing"
+
+func TestExtractRTF(t *testing.T) {
+	tests := []struct {
+		name string
+		rtf  string
+		want string
+	}{
+		{name: "paragraphs", rtf: `{\rtf1\ansi Hello\par World}`, want: "Hello\nWorld"},
+		{name: "skipped destinations", rtf: `{\rtf1{\fonttbl{\f0 Arial;}}{\colortbl;\red0\green0\blue0;}` +
+			`{\info{\title Secret}}\f0 Body}`, want: "Body"},
+		{name: "ignorable destinations", rtf: `{\rtf1{\*\generator Writer;}{\*\unknown hidden}Text}`, want: "Text"},
+		{name: "hexadecimal escapes", rtf: `{\rtf1 caf\'e9 \'93quoted\'94}`, want: "café “quoted”"},
+		{name: "unicode with fallback", rtf: `{\rtf1\uc1 na\u239?ve}`, want: "naïve"},
+		{name: "unicode with longer fallback", rtf: `{\rtf1\uc2\u8364 EUR}`, want: "€R"},
+		{name: "negative unicode", rtf: `{\rtf1 \u-3913?}`, want: "\uf0b7"},
+		{name: "escaped symbols", rtf: `{\rtf1 a\{b\}c\\d\~e\_f}`, want: "a{b}c\\d e-f"},
+		{name: "tabs and cells", rtf: `{\rtf1 a\tab b\cell c\row}`, want: "a\tb\tc\n"},
+		{name: "line breaks in the source ignored", rtf: "{\\rtf1 one\r\ntwo}", want: "onetwo"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := extractRTF([]byte(test.rtf))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+/*
+ * Copyright (C) 2026 Simone Pezzano
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+// Document is a document recorded in a knowledge base memory, which its knowledge chunks are made from. The version
+// grows every time the document is recorded with changes.
+type Document struct {
+	ID          uuid.UUID                            `gorm:"primary_key;type:uuid;default:gen_random_uuid();<-:create"`
+	Memory      string                               `gorm:"not null;uniqueIndex:documents_identity_memory_name_idx"`
+	Name        string                               `gorm:"not null;uniqueIndex:documents_identity_memory_name_idx"`
+	IdentityID  string                               `gorm:"not null;uniqueIndex:documents_identity_memory_name_idx"`
+	Tags        datatypes.JSONSlice[string]          `gorm:"not null"`
+	ContentHash string                               `gorm:"not null;default:''"`
+	Size        int64                                `gorm:"not null;default:0"`
+	ChunkCount  int                                  `gorm:"not null;default:0"`
+	Version     int                                  `gorm:"not null;default:1"`
+	Chunking    datatypes.JSONType[ChunkingSettings] `gorm:"not null;default:'{}'"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// DocumentContent is what a document was last submitted with: its text and, when uploaded as a file, the file itself
+type DocumentContent struct {
+	DocumentID uuid.UUID `gorm:"primary_key;type:uuid"`
+	Text       string    `gorm:"not null"`
+	FileName   *string
+	MediaType  *string
+	File       []byte
+	// FileHash is the SHA-256 of the file, empty when the document was submitted as text
+	FileHash string `gorm:"not null;default:''"`
+}
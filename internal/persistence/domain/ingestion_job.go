@@ -0,0 +1,54 @@
+/*
+ * Copyright (C) 2026 Simone Pezzano
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+// IngestionJob is a document waiting to be recorded, or being recorded, by a background worker. The worker holding a
+// job renews its lease while working on it: a job whose lease expires is picked up again by another worker.
+type IngestionJob struct {
+	ID              uuid.UUID                   `gorm:"primary_key;type:uuid;default:gen_random_uuid();<-:create"`
+	IdentityID      string                      `gorm:"not null;index:ingestion_jobs_document_idx"`
+	Memory          string                      `gorm:"not null;index:ingestion_jobs_document_idx"`
+	Document        string                      `gorm:"not null;index:ingestion_jobs_document_idx"`
+	Tags            datatypes.JSONSlice[string] `gorm:"not null"`
+	Content         string                      `gorm:"not null"`
+	Format          string                      `gorm:"not null"`
+	FileName        *string
+	MediaType       *string
+	File            []byte
+	Chunking        datatypes.JSONType[ChunkingSettings] `gorm:"not null;default:'{}'"`
+	State           string                               `gorm:"not null;index:ingestion_jobs_state_idx"`
+	Attempts        int                                  `gorm:"not null;default:0"`
+	ChunksToEmbed   int                                  `gorm:"not null;default:0"`
+	ChunksEmbedded  int                                  `gorm:"not null;default:0"`
+	Error           *string
+	DocumentVersion *int
+	LeaseToken      *uuid.UUID `gorm:"type:uuid"`
+	LeaseExpiresAt  *time.Time
+	AvailableAt     time.Time `gorm:"not null;default:now();index:ingestion_jobs_state_idx"`
+	StartedAt       *time.Time
+	FinishedAt      *time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
@@ -23,15 +23,32 @@ import (
 	"gorm.io/datatypes"
 )
 
-// Ollama vector size 2560
-
 type KnowledgeChunk struct {
-	ID         uuid.UUID                   `gorm:"primary_key;type:uuid;default:gen_random_uuid();<-:create"`
-	Memory     string                      `gorm:"not null"`
-	Document   string                      `gorm:"not null"`
-	Tags       datatypes.JSONSlice[string] `gorm:"not null"`
-	Chunk      string                      `gorm:"not null"`
-	Embedding  pgvector.Vector             `gorm:"type:vector(3072); not null"`
-	IdentityID string                      `gorm:"not null"`
-	Distance   float64                     `gorm:"column:distance;<-:false;-:migration"`
+	ID                uuid.UUID                            `gorm:"primary_key;type:uuid;default:gen_random_uuid();<-:create"`
+	Memory            string                               `gorm:"not null;index:knowledge_chunks_document_ordinal_idx,priority:2"`
+	Document          string                               `gorm:"not null;index:knowledge_chunks_document_ordinal_idx,priority:3"`
+	Tags              datatypes.JSONSlice[string]          `gorm:"not null"`
+	Chunk             string                               `gorm:"not null"`
+	ContentHash       string                               `gorm:"not null;default:''"`
+	Embedding         pgvector.Vector                      `gorm:"type:vector; not null"`
+	EmbeddingProvider string                               `gorm:"not null;default:''"`
+	EmbeddingModel    string                               `gorm:"not null;default:''"`
+	IdentityID        string                               `gorm:"not null;index:knowledge_chunks_document_ordinal_idx,priority:1"`
+	Chunking          datatypes.JSONType[ChunkingSettings] `gorm:"not null;default:'{}'"`
+	Symbol            *string
+	StartLine         *int
+	EndLine           *int
+	Ordinal           *int `gorm:"index:knowledge_chunks_document_ordinal_idx,priority:4"`
+	StartOffset       *int
+	EndOffset         *int
+	Headings          datatypes.JSONSlice[string] `gorm:"not null;default:'[]'"`
+	Distance          *float64                    `gorm:"column:distance;<-:false;-:migration"`
+	Context           *ChunkContext               `gorm:"-"`
+}
+
+// ChunkContext is a chunk merged with its neighbours in the same document
+type ChunkContext struct {
+	Chunk        string
+	StartOrdinal int
+	EndOrdinal   int
 }
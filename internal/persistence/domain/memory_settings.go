@@ -0,0 +1,41 @@
+/*
+ * Copyright (C) 2026 Simone Pezzano
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package domain
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+// ChunkingSettings describes how documents are split into chunks. In the settings of a memory, the fields left unset
+// fall back to the configured defaults. On a chunk, every field is set, recording how the chunk was produced.
+type ChunkingSettings struct {
+	Strategy             string   `json:"strategy,omitempty"`
+	Size                 *int     `json:"size,omitempty"`
+	Overlap              *int     `json:"overlap,omitempty"`
+	Unit                 string   `json:"unit,omitempty"`
+	Encoding             string   `json:"encoding,omitempty"`
+	BreakpointPercentile *float64 `json:"breakpoint_percentile,omitempty"`
+}
+
+type MemorySettings struct {
+	ID         uuid.UUID                            `gorm:"primary_key;type:uuid;default:gen_random_uuid();<-:create"`
+	Memory     string                               `gorm:"not null;uniqueIndex:memory_settings_identity_memory_idx"`
+	IdentityID string                               `gorm:"not null;uniqueIndex:memory_settings_identity_memory_idx"`
+	Chunking   datatypes.JSONType[ChunkingSettings] `gorm:"not null"`
+}
@@ -24,13 +24,15 @@ import (
 )
 
 type Recipe struct {
-	ID          uuid.UUID                   `gorm:"primary_key;type:uuid;default:gen_random_uuid();<-:create"`
-	Name        string                      `gorm:"not null"`
-	Description string                      `gorm:"not null"`
-	Memory      string                      `gorm:"not null"`
-	Tags        datatypes.JSONSlice[string] `gorm:"not null"`
-	Content     string                      `gorm:"not null"`
-	IdentityID  string                      `gorm:"not null"`
-	Embedding   pgvector.Vector             `gorm:"type:vector(3072); not null"`
-	Distance    float64                     `gorm:"column:distance;<-:false;-:migration"`
+	ID                uuid.UUID                   `gorm:"primary_key;type:uuid;default:gen_random_uuid();<-:create"`
+	Name              string                      `gorm:"not null"`
+	Description       string                      `gorm:"not null"`
+	Memory            string                      `gorm:"not null"`
+	Tags              datatypes.JSONSlice[string] `gorm:"not null"`
+	Content           string                      `gorm:"not null"`
+	IdentityID        string                      `gorm:"not null"`
+	Embedding         pgvector.Vector             `gorm:"type:vector; not null"`
+	EmbeddingProvider string                      `gorm:"not null;default:''"`
+	EmbeddingModel    string                      `gorm:"not null;default:''"`
+	Distance          *float64                    `gorm:"column:distance;<-:false;-:migration"`
 }
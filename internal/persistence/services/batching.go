@@ -0,0 +1,46 @@
+/*
+ * Copyright (C) 2026 Simone Pezzano
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package services
+
+import (
+	"context"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// embedInBatches splits the input in batches of batchSize, hands them to the embed function using at most
+// concurrency workers, and reassembles the vectors preserving the input order. The first failure cancels the
+// batches still in flight.
+func embedInBatches(ctx context.Context, input []string, batchSize int, concurrency int,
+	embed func(ctx context.Context, batch []string) ([][]float32, error)) ([][]float32, error) {
+	vectors := make([][]float32, len(input))
+	group, ctx := errgroup.WithContext(ctx)
+	group.SetLimit(concurrency)
+	for start := 0; start < len(input); start += batchSize {
+		end := min(start+batchSize, len(input))
+		group.Go(func() error {
+			batchVectors, err := embed(ctx, input[start:end])
+			if err != nil {
+				return err
+			}
+			copy(vectors[start:end], batchVectors)
+			return nil
+		})
+	}
+	return vectors, group.Wait()
+}
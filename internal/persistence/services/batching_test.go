@@ -0,0 +1,93 @@
+/*
+ * Copyright (C) 2026 Simone Pezzano
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestEmbedInBatches(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       int
+		batchSize   int
+		concurrency int
+		failing     int
+		wantBatches []int
+		wantErr     bool
+	}{
+		{name: "empty", input: 0, batchSize: 2, concurrency: 1, failing: -1, wantBatches: []int{}},
+		{name: "single batch", input: 3, batchSize: 4, concurrency: 1, failing: -1, wantBatches: []int{3}},
+		{name: "exact batches", input: 4, batchSize: 2, concurrency: 2, failing: -1, wantBatches: []int{2, 2}},
+		{name: "last batch shorter", input: 5, batchSize: 2, concurrency: 3, failing: -1,
+			wantBatches: []int{2, 2, 1}},
+		{name: "failing batch", input: 5, batchSize: 2, concurrency: 1, failing: 2, wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			input := make([]string, test.input)
+			for i := range input {
+				input[i] = strconv.Itoa(i)
+			}
+			var mu sync.Mutex
+			batches := make(map[int]int)
+			vectors, err := embedInBatches(context.Background(), input, test.batchSize, test.concurrency,
+				func(_ context.Context, batch []string) ([][]float32, error) {
+					first, _ := strconv.Atoi(batch[0])
+					if first == test.failing {
+						return nil, errors.New("failed")
+					}
+					mu.Lock()
+					batches[first/test.batchSize] = len(batch)
+					mu.Unlock()
+					res := make([][]float32, len(batch))
+					for i, item := range batch {
+						value, _ := strconv.Atoi(item)
+						res[i] = []float32{float32(value)}
+					}
+					return res, nil
+				})
+			if test.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, vector := range vectors {
+				if !reflect.DeepEqual(vector, []float32{float32(i)}) {
+					t.Errorf("vector %d is %v", i, vector)
+				}
+			}
+			sizes := make([]int, len(batches))
+			for i := range sizes {
+				sizes[i] = batches[i]
+			}
+			if !reflect.DeepEqual(sizes, test.wantBatches) {
+				t.Errorf("batch sizes are %v, want %v", sizes, test.wantBatches)
+			}
+		})
+	}
+}
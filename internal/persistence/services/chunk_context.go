@@ -0,0 +1,203 @@
+/*
+ * Copyright (C) 2026 Simone Pezzano
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package services
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// maxLocateSkippedLines is the number of leading lines a chunk may carry that are not contiguous with the rest of
+	// it in its document, such as the heading hierarchy of a markdown chunk or the header of a Go chunk
+	maxLocateSkippedLines = 8
+	// locateSlack is how far past the expected end of a chunk it is searched for in its document
+	locateSlack = 1024
+	// minMergedOverlap is the shortest text two neighbouring chunks must share to be merged on it, rather than joined
+	minMergedOverlap = 8
+)
+
+var (
+	markdownHeading = regexp.MustCompile(`^ {0,3}(#{1,6})[ \t]+(.*?)[ \t#]*$`)
+	markdownFence   = regexp.MustCompile("^ {0,3}(```|~~~)")
+)
+
+// spacedText is a text whose whitespace runs are collapsed to single spaces, along with the byte offset in the
+// original text of each of its bytes
+type spacedText struct {
+	text   string
+	origin []int
+}
+
+func collapseSpace(text string) spacedText {
+	var builder strings.Builder
+	origin := make([]int, 0, len(text))
+	space := false
+	for i, r := range text {
+		if unicode.IsSpace(r) {
+			space = builder.Len() > 0
+			continue
+		}
+		if space {
+			builder.WriteByte(' ')
+			origin = append(origin, i)
+			space = false
+		}
+		builder.WriteRune(r)
+		for k := range utf8.RuneLen(r) {
+			origin = append(origin, i+k)
+		}
+	}
+	return spacedText{text: builder.String(), origin: origin}
+}
+
+// positionChunks numbers the chunks of a document and finds the byte range each of them covers in its text,
+// regardless of whitespace. Chunks are searched in order, each one starting after the start of the previous one. The
+// chunks that cannot be found, as their splitter rewrote them, are left without offsets. Unless withHeadings is false,
+// each chunk located in the text gets the markdown headings it falls under.
+func positionChunks(content string, chunks []chunk, withHeadings bool) {
+	spaced := collapseSpace(content)
+	headings := make([]markdownSection, 0)
+	if withHeadings {
+		headings = markdownSections(content)
+	}
+	cursor := 0
+	// missed is the length of the chunks not found since the last one found, which the next one can start after
+	missed := 0
+	for i := range chunks {
+		chunks[i].ordinal = i
+		lines := strings.Split(chunks[i].text, "\n")
+		// A chunk starts within the previous one, so the search does not need to go much further
+		reach := min(len(spaced.text), cursor+missed+2*len(chunks[i].text)+locateSlack)
+		missed += len(chunks[i].text)
+		for skip := 0; skip < min(len(lines), maxLocateSkippedLines+1); skip++ {
+			target := strings.Join(strings.Fields(strings.Join(lines[skip:], "\n")), " ")
+			if target == "" {
+				break
+			}
+			at := strings.Index(spaced.text[cursor:reach], target)
+			if at < 0 {
+				continue
+			}
+			start := spaced.origin[cursor+at]
+			end := spaced.origin[cursor+at+len(target)-1] + 1
+			chunks[i].start, chunks[i].end = &start, &end
+			chunks[i].headings = headingsAt(headings, start)
+			cursor += at + 1
+			missed = 0
+			break
+		}
+	}
+}
+
+// markdownSection is a markdown heading, with the byte offset where its section starts
+type markdownSection struct {
+	offset int
+	level  int
+	title  string
+}
+
+// markdownSections lists the ATX headings of a markdown text, skipping fenced code blocks
+func markdownSections(content string) []markdownSection {
+	sections := make([]markdownSection, 0)
+	fence := ""
+	offset := 0
+	for _, line := range strings.SplitAfter(content, "\n") {
+		trimmed := strings.TrimRight(line, "\r\n")
+		if match := markdownFence.FindStringSubmatch(trimmed); match != nil {
+			if fence == "" {
+				fence = match[1]
+			} else if fence == match[1] {
+				fence = ""
+			}
+		} else if match := markdownHeading.FindStringSubmatch(trimmed); match != nil && fence == "" &&
+			match[2] != "" {
+			sections = append(sections, markdownSection{offset: offset, level: len(match[1]), title: match[2]})
+		}
+		offset += len(line)
+	}
+	return sections
+}
+
+// headingsAt returns the breadcrumb of the headings a byte offset falls under, from the outermost
+func headingsAt(sections []markdownSection, offset int) []string {
+	stack := make([]markdownSection, 0)
+	for _, section := range sections {
+		if section.offset > offset {
+			break
+		}
+		for len(stack) > 0 && stack[len(stack)-1].level >= section.level {
+			stack = stack[:len(stack)-1]
+		}
+		stack = append(stack, section)
+	}
+	breadcrumb := make([]string, len(stack))
+	for i, section := range stack {
+		breadcrumb[i] = section.title
+	}
+	return breadcrumb
+}
+
+// mergeChunkTexts joins consecutive chunks of a document, dropping the text each chunk repeats from the previous one:
+// the end of the previous chunk it overlaps, the headings the markdown splitter repeats at its start, and the header
+// of path and package the Go splitter repeats in every chunk
+func mergeChunkTexts(texts []string) string {
+	var builder strings.Builder
+	previous := ""
+	for i, text := range texts {
+		current := text
+		if header := goChunkHeader.FindString(text); header != "" && strings.HasPrefix(previous, header) {
+			current = current[len(header):]
+		}
+		shared := leadingHeadings(previous)
+		for _, heading := range leadingHeadings(text) {
+			if len(shared) == 0 || shared[0] != heading {
+				break
+			}
+			shared = shared[1:]
+			current = strings.TrimPrefix(current[len(heading):], "\n")
+		}
+		overlap := 0
+		for k := min(len(previous), len(current)); k >= minMergedOverlap; k-- {
+			if strings.HasSuffix(previous, current[:k]) {
+				overlap = k
+				break
+			}
+		}
+		if i > 0 && overlap == 0 {
+			builder.WriteString("\n\n")
+		}
+		builder.WriteString(current[overlap:])
+		previous = text
+	}
+	return builder.String()
+}
+
+// leadingHeadings returns the markdown heading lines a chunk starts with
+func leadingHeadings(text string) []string {
+	headings := make([]string, 0)
+	for _, line := range strings.Split(text, "\n") {
+		if !markdownHeading.MatchString(line) {
+			break
+		}
+		headings = append(headings, line)
+	}
+	return headings
+}
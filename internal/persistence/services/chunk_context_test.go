@@ -0,0 +1,102 @@
+/*
+ * Copyright (C) 2026 Simone Pezzano
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/samber/lo"
+)
+
+// chunkPosition is the part of a chunk positionChunks fills, with -1 offsets for the chunks it cannot find and nil
+// headings for the chunks under none
+type chunkPosition struct {
+	ordinal  int
+	start    int
+	end      int
+	headings []string
+}
+
+func TestPositionChunks(t *testing.T) {
+	markdown := "# Title\n\nIntro text.\n\n## Part\n\nBody text."
+	tests := []struct {
+		name         string
+		content      string
+		chunks       []string
+		withHeadings bool
+		want         []chunkPosition
+	}{
+		{name: "overlapping chunks", content: "alpha beta gamma delta",
+			chunks: []string{"alpha beta", "beta gamma", "gamma delta"},
+			want:   []chunkPosition{{0, 0, 10, nil}, {1, 6, 16, nil}, {2, 11, 22, nil}}},
+		{name: "whitespace collapsed", content: "alpha\n\nbeta  gamma", chunks: []string{"alpha beta", "beta gamma"},
+			want: []chunkPosition{{0, 0, 11, nil}, {1, 7, 18, nil}}},
+		{name: "offsets in bytes", content: "héllo wörld", chunks: []string{"héllo", "wörld"},
+			want: []chunkPosition{{0, 0, 6, nil}, {1, 7, 13, nil}}},
+		{name: "chunks not found", content: "alpha beta", chunks: []string{"zeta", "beta"},
+			want: []chunkPosition{{0, -1, -1, nil}, {1, 6, 10, nil}}},
+		{name: "repeated headings skipped", content: markdown, withHeadings: true,
+			chunks: []string{"# Title\nIntro text.", "# Title\n## Part\nBody text."},
+			want:   []chunkPosition{{0, 0, 20, []string{"Title"}}, {1, 22, 41, []string{"Title", "Part"}}}},
+		{name: "without headings", content: markdown, chunks: []string{"Body text."},
+			want: []chunkPosition{{0, 31, 41, nil}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			chunks := lo.Map(test.chunks, func(text string, _ int) chunk {
+				return chunk{text: text}
+			})
+			positionChunks(test.content, chunks, test.withHeadings)
+			got := lo.Map(chunks, func(item chunk, _ int) chunkPosition {
+				return chunkPosition{item.ordinal, lo.FromPtrOr(item.start, -1), lo.FromPtrOr(item.end, -1),
+					lo.Ternary(len(item.headings) > 0, item.headings, nil)}
+			})
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestMergeChunkTexts(t *testing.T) {
+	tests := []struct {
+		name  string
+		texts []string
+		want  string
+	}{
+		{name: "single chunk", texts: []string{"alpha beta"}, want: "alpha beta"},
+		{name: "overlap merged", texts: []string{"one two three four", "three four five six"},
+			want: "one two three four five six"},
+		{name: "short overlap joined", texts: []string{"one two", "two three"}, want: "one two\n\ntwo three"},
+		{name: "repeated headings dropped", texts: []string{"# Title\n## Part\nFirst.", "# Title\n## Part\nSecond."},
+			want: "# Title\n## Part\nFirst.\n\nSecond."},
+		{name: "new headings kept", texts: []string{"# Title\n## Part\nFirst.", "# Title\n## Other\nSecond."},
+			want: "# Title\n## Part\nFirst.\n\n## Other\nSecond."},
+		{name: "repeated go header dropped",
+			texts: []string{"// a/b.go\npackage b\n\nfunc A() {}", "// a/b.go\npackage b\n\nfunc B() {}"},
+			want:  "// a/b.go\npackage b\n\nfunc A() {}\n\nfunc B() {}"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := mergeChunkTexts(test.texts); got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
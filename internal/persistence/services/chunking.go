@@ -0,0 +1,404 @@
+/*
+ * Copyright (C) 2026 Simone Pezzano
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/pgvector/pgvector-go"
+	"github.com/pkoukk/tiktoken-go"
+	"github.com/samber/lo"
+	"github.com/theirish81/meta/internal/config"
+	"github.com/theirish81/meta/internal/extraction"
+	"github.com/theirish81/meta/internal/persistence/domain"
+	"github.com/tmc/langchaingo/textsplitter"
+)
+
+const (
+	// ChunkingAuto splits markdown documents by their structure, and everything else recursively
+	ChunkingAuto = "auto"
+	// ChunkingRecursive splits by paragraphs, then lines, then words, until the chunks fit
+	ChunkingRecursive = "recursive"
+	// ChunkingMarkdown splits by headings, keeping the heading hierarchy in every chunk
+	ChunkingMarkdown = "markdown"
+	// ChunkingSentence packs whole sentences into chunks
+	ChunkingSentence = "sentence"
+	// ChunkingParagraph packs whole paragraphs into chunks
+	ChunkingParagraph = "paragraph"
+	// ChunkingSemantic cuts between sentences where the topic changes, as told by their embeddings
+	ChunkingSemantic = "semantic"
+	// ChunkingCode splits source code by definitions, recording the symbol and the lines of each chunk
+	ChunkingCode = "code"
+
+	ChunkUnitCharacters = "characters"
+	ChunkUnitTokens     = "tokens"
+)
+
+var (
+	chunkingStrategies = []string{ChunkingAuto, ChunkingRecursive, ChunkingMarkdown, ChunkingSentence,
+		ChunkingParagraph, ChunkingSemantic, ChunkingCode}
+	chunkUnits     = []string{ChunkUnitCharacters, ChunkUnitTokens}
+	chunkEncodings = []string{tiktoken.MODEL_CL100K_BASE, tiktoken.MODEL_P50K_BASE, tiktoken.MODEL_R50K_BASE}
+
+	paragraphBreak = regexp.MustCompile(`\n\s*\n`)
+	// sentenceEnd matches the punctuation closing a sentence, with the quotes and brackets following it
+	sentenceEnd = regexp.MustCompile(`[.!?…]+["'”’)\]]*\s+`)
+
+	// tokenizers holds the tokenizers loaded so far, by encoding
+	tokenizers     = map[string]*tiktoken.Tiktoken{}
+	tokenizersLock sync.Mutex
+)
+
+// tokenizerTimeout is how long loading a tiktoken encoding can take, download included
+const tokenizerTimeout = 30 * time.Second
+
+// chunk is a piece of a document. Chunks of source code carry the symbol they define and their one-based lines.
+type chunk struct {
+	text      string
+	symbol    string
+	startLine int
+	endLine   int
+	// ordinal is the position of the chunk in its document, from 0
+	ordinal int
+	// start and end are the byte offsets of the chunk in the text of its document, when found there
+	start    *int
+	end      *int
+	headings []string
+}
+
+// defaultChunking returns the configured chunking settings
+func defaultChunking() domain.ChunkingSettings {
+	return domain.ChunkingSettings{
+		Strategy:             config.Instance.ChunkStrategy,
+		Size:                 lo.ToPtr(config.Instance.ChunkSize),
+		Overlap:              lo.ToPtr(config.Instance.ChunkOverlap),
+		Unit:                 config.Instance.ChunkUnit,
+		Encoding:             config.Instance.ChunkEncoding,
+		BreakpointPercentile: lo.ToPtr(config.Instance.ChunkBreakpointPercentile),
+	}
+}
+
+// mergeChunking returns the base settings, overridden by the fields set in each of the overrides, in order
+func mergeChunking(base domain.ChunkingSettings, overrides ...*domain.ChunkingSettings) domain.ChunkingSettings {
+	for _, override := range overrides {
+		if override == nil {
+			continue
+		}
+		base.Strategy = lo.CoalesceOrEmpty(override.Strategy, base.Strategy)
+		base.Size = lo.CoalesceOrEmpty(override.Size, base.Size)
+		base.Overlap = lo.CoalesceOrEmpty(override.Overlap, base.Overlap)
+		base.Unit = lo.CoalesceOrEmpty(override.Unit, base.Unit)
+		base.Encoding = lo.CoalesceOrEmpty(override.Encoding, base.Encoding)
+		base.BreakpointPercentile = lo.CoalesceOrEmpty(override.BreakpointPercentile, base.BreakpointPercentile)
+	}
+	return base
+}
+
+// validateChunking verifies the fields that are set. The overlap can only be checked against the size once the
+// settings are resolved.
+func validateChunking(settings domain.ChunkingSettings) error {
+	switch {
+	case settings.Strategy != "" && !slices.Contains(chunkingStrategies, settings.Strategy):
+		return fmt.Errorf("%w: strategy must be one of %v", ErrInvalidChunking, chunkingStrategies)
+	case settings.Unit != "" && !slices.Contains(chunkUnits, settings.Unit):
+		return fmt.Errorf("%w: unit must be one of %v", ErrInvalidChunking, chunkUnits)
+	case settings.Encoding != "" && !slices.Contains(chunkEncodings, settings.Encoding):
+		return fmt.Errorf("%w: encoding must be one of %v", ErrInvalidChunking, chunkEncodings)
+	case settings.Size != nil && *settings.Size < 1:
+		return fmt.Errorf("%w: size must be at least 1", ErrInvalidChunking)
+	case settings.Overlap != nil && *settings.Overlap < 0:
+		return fmt.Errorf("%w: overlap cannot be negative", ErrInvalidChunking)
+	case settings.Size != nil && settings.Overlap != nil && *settings.Overlap >= *settings.Size:
+		return fmt.Errorf("%w: overlap must be smaller than size", ErrInvalidChunking)
+	case settings.BreakpointPercentile != nil && (*settings.BreakpointPercentile < 0 ||
+		*settings.BreakpointPercentile > 100):
+		return fmt.Errorf("%w: breakpoint_percentile must be between 0 and 100", ErrInvalidChunking)
+	}
+	return nil
+}
+
+// resolveChunking layers the overrides on top of the configured settings, and pins the strategy the document is
+// split with, so that the settings recorded on the chunks describe them fully
+func resolveChunking(format extraction.Format, overrides ...*domain.ChunkingSettings) (domain.ChunkingSettings,
+	error) {
+	for _, override := range overrides {
+		if override != nil {
+			if err := validateChunking(*override); err != nil {
+				return domain.ChunkingSettings{}, err
+			}
+		}
+	}
+	settings := mergeChunking(defaultChunking(), overrides...)
+	if err := validateChunking(settings); err != nil {
+		return settings, err
+	}
+	if settings.Strategy == ChunkingAuto {
+		switch format {
+		case extraction.FormatMarkdown:
+			settings.Strategy = ChunkingMarkdown
+		case extraction.FormatGo, extraction.FormatCode:
+			settings.Strategy = ChunkingCode
+		default:
+			settings.Strategy = ChunkingRecursive
+		}
+	}
+	if settings.Unit == ChunkUnitCharacters {
+		settings.Encoding = ""
+	}
+	if settings.Strategy != ChunkingSemantic {
+		settings.BreakpointPercentile = nil
+	}
+	if _, err := chunkLength(settings); err != nil {
+		return settings, err
+	}
+	return settings, nil
+}
+
+// initTokenizers loads the configured encoding on startup when chunks are measured in tokens, so that ingestions
+// do not wait for its download. The other encodings are loaded when first asked for.
+func initTokenizers() error {
+	if config.Instance.ChunkUnit != ChunkUnitTokens {
+		return nil
+	}
+	_, err := tokenizer(config.Instance.ChunkEncoding)
+	return err
+}
+
+// tokenizer returns the tokenizer of an encoding, loading it when first asked for. tiktoken downloads the encoding
+// unless found in TIKTOKEN_CACHE_DIR, with no timeout of its own, so the load is abandoned after tokenizerTimeout.
+func tokenizer(encoding string) (*tiktoken.Tiktoken, error) {
+	tokenizersLock.Lock()
+	defer tokenizersLock.Unlock()
+	if loaded, ok := tokenizers[encoding]; ok {
+		return loaded, nil
+	}
+	type result struct {
+		tokenizer *tiktoken.Tiktoken
+		err       error
+	}
+	done := make(chan result, 1)
+	go func() {
+		loaded, err := tiktoken.GetEncoding(encoding)
+		done <- result{loaded, err}
+	}()
+	select {
+	case res := <-done:
+		if res.err != nil {
+			return nil, fmt.Errorf("loading the %s encoding: %w", encoding, res.err)
+		}
+		tokenizers[encoding] = res.tokenizer
+		return res.tokenizer, nil
+	case <-time.After(tokenizerTimeout):
+		return nil, fmt.Errorf("loading the %s encoding: timed out after %s", encoding, tokenizerTimeout)
+	}
+}
+
+// chunkLength returns the function measuring chunks in the unit of the settings
+func chunkLength(settings domain.ChunkingSettings) (func(string) int, error) {
+	if settings.Unit != ChunkUnitTokens {
+		return utf8.RuneCountInString, nil
+	}
+	encoding, err := tokenizer(settings.Encoding)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidChunking, err)
+	}
+	return func(text string) int {
+		return len(encoding.Encode(text, nil, nil))
+	}, nil
+}
+
+// chunkText splits a document into chunks, according to resolved settings
+func chunkText(ctx context.Context, document string, content string, format extraction.Format,
+	settings domain.ChunkingSettings) ([]chunk, error) {
+	var chunks []chunk
+	if settings.Strategy == ChunkingCode {
+		var err error
+		if chunks, err = chunkCode(document, content, format, settings); err != nil {
+			return nil, err
+		}
+	} else {
+		texts, err := splitText(ctx, content, settings)
+		if err != nil {
+			return nil, err
+		}
+		chunks = lo.Map(texts, func(text string, _ int) chunk {
+			return chunk{text: text}
+		})
+	}
+	positionChunks(content, chunks, settings.Strategy != ChunkingCode)
+	return chunks, nil
+}
+
+func chunkCode(document string, content string, format extraction.Format,
+	settings domain.ChunkingSettings) ([]chunk, error) {
+	length, err := chunkLength(settings)
+	if err != nil {
+		return nil, err
+	}
+	splitter := newCodeSplitter(content, *settings.Size, length, textsplitter.NewRecursiveCharacter(
+		textsplitter.WithChunkSize(*settings.Size),
+		textsplitter.WithChunkOverlap(*settings.Overlap),
+		textsplitter.WithLenFunc(length)))
+	if format == extraction.FormatGo {
+		return splitter.splitGo(document, content)
+	}
+	return splitter.split()
+}
+
+func splitText(ctx context.Context, content string, settings domain.ChunkingSettings) ([]string, error) {
+	length, err := chunkLength(settings)
+	if err != nil {
+		return nil, err
+	}
+	size, overlap := *settings.Size, *settings.Overlap
+	recursive := textsplitter.NewRecursiveCharacter(
+		textsplitter.WithChunkSize(size),
+		textsplitter.WithChunkOverlap(overlap),
+		textsplitter.WithLenFunc(length))
+	switch settings.Strategy {
+	case ChunkingMarkdown:
+		return textsplitter.NewMarkdownTextSplitter(
+			textsplitter.WithChunkSize(size),
+			textsplitter.WithChunkOverlap(overlap),
+			textsplitter.WithLenFunc(length),
+			textsplitter.WithHeadingHierarchy(true)).SplitText(content)
+	case ChunkingSentence:
+		return packUnits(splitSentences(content), " ", size, overlap, length, recursive)
+	case ChunkingParagraph:
+		return packUnits(splitParagraphs(content), "\n\n", size, overlap, length, recursive)
+	case ChunkingSemantic:
+		return semanticChunks(ctx, content, *settings.BreakpointPercentile, size, length, recursive)
+	default:
+		return recursive.SplitText(content)
+	}
+}
+
+func splitParagraphs(content string) []string {
+	return lo.FilterMap(paragraphBreak.Split(content, -1), func(paragraph string, _ int) (string, bool) {
+		paragraph = strings.TrimSpace(paragraph)
+		return paragraph, paragraph != ""
+	})
+}
+
+// splitSentences splits the paragraphs of a document into sentences. Abbreviations followed by a space end a
+// sentence as well, which is harmless once sentences are packed into chunks.
+func splitSentences(content string) []string {
+	sentences := make([]string, 0)
+	for _, paragraph := range splitParagraphs(content) {
+		start := 0
+		for _, end := range sentenceEnd.FindAllStringIndex(paragraph, -1) {
+			sentences = append(sentences, strings.TrimSpace(paragraph[start:end[1]]))
+			start = end[1]
+		}
+		if rest := strings.TrimSpace(paragraph[start:]); rest != "" {
+			sentences = append(sentences, rest)
+		}
+	}
+	return sentences
+}
+
+// packUnits joins consecutive units into chunks of up to size, repeating the last units of a chunk at the start of
+// the next one, as long as they fit in the overlap. Units larger than a chunk are split on their own.
+func packUnits(units []string, separator string, size int, overlap int, length func(string) int,
+	fallback textsplitter.TextSplitter) ([]string, error) {
+	chunks := make([]string, 0)
+	current := make([]string, 0)
+	for _, unit := range units {
+		if length(unit) > size {
+			if len(current) > 0 {
+				chunks = append(chunks, strings.Join(current, separator))
+				current = current[:0]
+			}
+			parts, err := fallback.SplitText(unit)
+			if err != nil {
+				return nil, err
+			}
+			chunks = append(chunks, parts...)
+			continue
+		}
+		if len(current) > 0 && length(strings.Join(append(current, unit), separator)) > size {
+			chunks = append(chunks, strings.Join(current, separator))
+			for len(current) > 0 && (length(strings.Join(current, separator)) > overlap ||
+				length(strings.Join(append(current, unit), separator)) > size) {
+				current = current[1:]
+			}
+		}
+		current = append(current, unit)
+	}
+	if len(current) > 0 {
+		chunks = append(chunks, strings.Join(current, separator))
+	}
+	return chunks, nil
+}
+
+// semanticChunks groups consecutive sentences, cutting where the distance between the embeddings of two neighbours
+// is above the given percentile of all such distances. Groups larger than a chunk are split further. Chunks do not
+// overlap, as they end where the topic changes.
+func semanticChunks(ctx context.Context, content string, percentile float64, size int, length func(string) int,
+	fallback textsplitter.TextSplitter) ([]string, error) {
+	sentences := splitSentences(content)
+	if len(sentences) < 2 {
+		return packUnits(sentences, " ", size, 0, length, fallback)
+	}
+	embeddings, err := Services.EmbeddingService.ExtractEmbeddings(ctx, PurposeDocument, sentences)
+	if err != nil {
+		return nil, err
+	}
+	distances := make([]float64, len(sentences)-1)
+	for i := range distances {
+		distances[i] = 1 - cosineSimilarity(pgvector.NewVector(embeddings[i].Vector),
+			pgvector.NewVector(embeddings[i+1].Vector))
+	}
+	breakpoint := percentileOf(distances, percentile)
+	chunks := make([]string, 0)
+	group := []string{sentences[0]}
+	for i, distance := range distances {
+		if distance > breakpoint {
+			parts, err := packUnits(group, " ", size, 0, length, fallback)
+			if err != nil {
+				return nil, err
+			}
+			chunks = append(chunks, parts...)
+			group = nil
+		}
+		group = append(group, sentences[i+1])
+	}
+	parts, err := packUnits(group, " ", size, 0, length, fallback)
+	if err != nil {
+		return nil, err
+	}
+	return append(chunks, parts...), nil
+}
+
+// percentileOf returns the value below which the given percentage of values fall, interpolating between ranks
+func percentileOf(values []float64, percentile float64) float64 {
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	rank := percentile / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+}
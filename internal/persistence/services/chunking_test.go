@@ -0,0 +1,148 @@
+/*
+ * Copyright (C) 2026 Simone Pezzano
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package services
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/samber/lo"
+	"github.com/tmc/langchaingo/textsplitter"
+)
+
+// embedderFunc is an embedding service computing each vector from its text alone
+type embedderFunc func(text string) []float32
+
+func (f embedderFunc) ExtractEmbeddings(_ context.Context, _ EmbeddingPurpose, input []string) ([]Embedding,
+	error) {
+	return lo.Map(input, func(text string, _ int) Embedding {
+		return Embedding{Text: text, Vector: f(text)}
+	}), nil
+}
+
+func characterSplitter(size int, overlap int) textsplitter.TextSplitter {
+	return textsplitter.NewRecursiveCharacter(textsplitter.WithChunkSize(size), textsplitter.WithChunkOverlap(overlap),
+		textsplitter.WithLenFunc(utf8.RuneCountInString))
+}
+
+func TestPackUnits(t *testing.T) {
+	tests := []struct {
+		name      string
+		units     []string
+		separator string
+		size      int
+		overlap   int
+		want      []string
+	}{
+		{name: "no units", units: []string{}, separator: " ", size: 5, want: []string{}},
+		{name: "all fit", units: []string{"aa", "bb"}, separator: " ", size: 5, want: []string{"aa bb"}},
+		{name: "packed up to the size", units: []string{"aa", "bb", "cc"}, separator: " ", size: 5,
+			want: []string{"aa bb", "cc"}},
+		{name: "separator counts", units: []string{"aa", "bb", "cc"}, separator: "\n\n", size: 5,
+			want: []string{"aa", "bb", "cc"}},
+		{name: "overlapping units", units: []string{"aa", "bb", "cc"}, separator: " ", size: 5, overlap: 2,
+			want: []string{"aa bb", "bb cc"}},
+		{name: "overlap larger than the last unit", units: []string{"aa", "bb", "cc", "dd"}, separator: " ",
+			size: 8, overlap: 5, want: []string{"aa bb cc", "bb cc dd"}},
+		{name: "oversized unit split on its own", units: []string{"aa", "bbbbbbbb", "cc"}, separator: " ",
+			size: 5, want: []string{"aa", "bbbbb", "bbb", "cc"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := packUnits(test.units, test.separator, test.size, test.overlap, utf8.RuneCountInString,
+				characterSplitter(test.size, 0))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestPercentileOf(t *testing.T) {
+	tests := []struct {
+		name       string
+		values     []float64
+		percentile float64
+		want       float64
+	}{
+		{name: "single value", values: []float64{3}, percentile: 95, want: 3},
+		{name: "minimum", values: []float64{3, 1, 2}, percentile: 0, want: 1},
+		{name: "maximum", values: []float64{3, 1, 2}, percentile: 100, want: 3},
+		{name: "median of unsorted values", values: []float64{5, 1, 4, 2, 3}, percentile: 50, want: 3},
+		{name: "interpolated", values: []float64{0, 10}, percentile: 25, want: 2.5},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			values := append([]float64(nil), test.values...)
+			if got := percentileOf(test.values, test.percentile); math.Abs(got-test.want) > 1e-9 {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+			if !reflect.DeepEqual(values, test.values) {
+				t.Errorf("the values were reordered to %v", test.values)
+			}
+		})
+	}
+}
+
+func TestSemanticChunks(t *testing.T) {
+	previous := Services.EmbeddingService
+	defer func() {
+		Services.EmbeddingService = previous
+	}()
+	// Sentences about cats point one way, the others another
+	Services.EmbeddingService = embedderFunc(func(text string) []float32 {
+		if strings.Contains(text, "Cats") {
+			return []float32{1, 0}
+		}
+		return []float32{0, 1}
+	})
+	tests := []struct {
+		name       string
+		content    string
+		percentile float64
+		size       int
+		want       []string
+	}{
+		{name: "single sentence", content: "Cats purr.", percentile: 50, size: 100, want: []string{"Cats purr."}},
+		{name: "cut where the topic changes", content: "Cats purr. Cats meow.\n\nStocks fell. Stocks rose.",
+			percentile: 50, size: 100, want: []string{"Cats purr. Cats meow.", "Stocks fell. Stocks rose."}},
+		{name: "no cut at the top percentile", content: "Cats purr. Stocks fell.", percentile: 100, size: 100,
+			want: []string{"Cats purr. Stocks fell."}},
+		{name: "groups larger than a chunk split further", content: "Cats purr. Cats meow. Stocks fell.",
+			percentile: 50, size: 12, want: []string{"Cats purr.", "Cats meow.", "Stocks fell."}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := semanticChunks(context.Background(), test.content, test.percentile, test.size,
+				utf8.RuneCountInString, characterSplitter(test.size, 0))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
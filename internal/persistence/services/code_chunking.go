@@ -0,0 +1,417 @@
+/*
+ * Copyright (C) 2026 Simone Pezzano
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package services
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/tmc/langchaingo/textsplitter"
+)
+
+var (
+	// codeDefinition matches the lines that define a named block in most languages
+	codeDefinition = regexp.MustCompile(`\b(?:func|function|def|class|fn|struct|interface|enum|trait|impl|module|` +
+		`namespace|record|object|protocol|extension|sub|proc|message|service|resource)\s+([A-Za-z_$][\w$]*)`)
+	// codeFunction matches C-style function definitions, where the name follows the modifiers and the return type
+	codeFunction = regexp.MustCompile(`^\s*(?:[\w$<>\[\],*&:]+\s+)+[*&]*([A-Za-z_$][\w$]*)\s*\(`)
+	// codeAssignment matches functions assigned to variables, as in JavaScript
+	codeAssignment = regexp.MustCompile(`\b(?:const|let|var)\s+([A-Za-z_$][\w$]*)\s*=\s*(?:async\s+)?` +
+		`(?:function\b|\([^)]*\)\s*=>|[A-Za-z_$][\w$]*\s*=>)`)
+	// codeKeywords are the words that look like a return type to codeFunction, while introducing a statement
+	codeKeywords = []string{"return", "if", "else", "while", "for", "switch", "new", "throw", "case", "await",
+		"yield", "delete", "typeof", "sizeof", "do", "in", "of", "not", "and", "or", "go", "defer"}
+	// codeContinuations are the words that carry on the block of the previous line at the same indentation
+	codeContinuations = []string{"else", "elif", "except", "finally", "catch", "rescue", "ensure", "when", "end"}
+	// goChunkHeader matches the header splitGo prepends to every chunk of a Go file
+	goChunkHeader = regexp.MustCompile(`^// .*\npackage \w+\n\n`)
+)
+
+// codeBlock is a range of lines of source code, [start, end) and zero based, defining symbol when named
+type codeBlock struct {
+	start  int
+	end    int
+	symbol string
+}
+
+// codeSplitter splits source code into chunks of whole definitions, recording the symbol and the lines of each
+type codeSplitter struct {
+	lines    []string
+	size     int
+	length   func(string) int
+	fallback textsplitter.TextSplitter
+	// header is prepended to every chunk, to give context to definitions out of their file
+	header string
+}
+
+func newCodeSplitter(content string, size int, length func(string) int,
+	fallback textsplitter.TextSplitter) *codeSplitter {
+	return &codeSplitter{
+		lines:    strings.Split(content, "\n"),
+		size:     size,
+		length:   length,
+		fallback: fallback,
+	}
+}
+
+// splitGo makes a chunk of every top level declaration of a Go file, along with its doc comment. Each chunk is
+// headed by the path of the file and the package clause. Files that do not parse are split as any other code.
+func (s *codeSplitter) splitGo(document string, content string) ([]chunk, error) {
+	files := token.NewFileSet()
+	file, err := parser.ParseFile(files, document, content, parser.ParseComments|parser.SkipObjectResolution)
+	if err != nil {
+		return s.split()
+	}
+	s.header = "// " + document + "\npackage " + file.Name.Name + "\n\n"
+	line := func(pos token.Pos) int {
+		return files.Position(pos).Line - 1
+	}
+	blocks := make([]codeBlock, 0)
+	if file.Doc != nil {
+		blocks = append(blocks, codeBlock{start: line(file.Doc.Pos()), end: line(file.Name.End()) + 1,
+			symbol: "package " + file.Name.Name})
+	}
+	for _, decl := range file.Decls {
+		block := codeBlock{start: line(decl.Pos()), end: line(decl.End()) + 1}
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if decl.Doc != nil {
+				block.start = line(decl.Doc.Pos())
+			}
+			block.symbol = goFuncName(decl)
+		case *ast.GenDecl:
+			if decl.Tok == token.IMPORT {
+				continue
+			}
+			if decl.Doc != nil {
+				block.start = line(decl.Doc.Pos())
+			}
+			block.symbol = strings.Join(goSpecNames(decl), ", ")
+		}
+		blocks = append(blocks, block)
+	}
+	res := make([]chunk, 0, len(blocks))
+	for _, block := range blocks {
+		chunks, err := s.fit(block)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, chunks...)
+	}
+	return res, nil
+}
+
+// goFuncName names functions by their name, and methods by their receiver type and name
+func goFuncName(decl *ast.FuncDecl) string {
+	if decl.Recv == nil || len(decl.Recv.List) == 0 {
+		return decl.Name.Name
+	}
+	receiver := decl.Recv.List[0].Type
+	for {
+		switch expr := receiver.(type) {
+		case *ast.StarExpr:
+			receiver = expr.X
+			continue
+		case *ast.IndexExpr:
+			receiver = expr.X
+			continue
+		case *ast.IndexListExpr:
+			receiver = expr.X
+			continue
+		case *ast.Ident:
+			return expr.Name + "." + decl.Name.Name
+		}
+		return decl.Name.Name
+	}
+}
+
+// goSpecNames returns the names of the types, constants or variables a declaration defines
+func goSpecNames(decl *ast.GenDecl) []string {
+	names := make([]string, 0)
+	for _, spec := range decl.Specs {
+		switch spec := spec.(type) {
+		case *ast.TypeSpec:
+			names = append(names, spec.Name.Name)
+		case *ast.ValueSpec:
+			for _, name := range spec.Names {
+				if name.Name != "_" {
+					names = append(names, name.Name)
+				}
+			}
+		}
+	}
+	return names
+}
+
+// split chunks source code of any language, by the blocks found at its outermost level
+func (s *codeSplitter) split() ([]chunk, error) {
+	return s.pack(s.blocks(0, len(s.lines)), "")
+}
+
+// fit returns a block as a single chunk when it fits, and otherwise splits it by the blocks nested in it
+func (s *codeSplitter) fit(block codeBlock) ([]chunk, error) {
+	if s.length(s.text(block)) <= s.size {
+		return []chunk{s.chunk(block)}, nil
+	}
+	// The first line past the comments opens the block, so the nested blocks are looked for after it
+	opening := block.start
+	for opening < block.end-1 && (strings.TrimSpace(s.lines[opening]) == "" ||
+		codeTrivia(strings.TrimSpace(s.lines[opening]))) {
+		opening++
+	}
+	nested := s.blocks(opening+1, block.end)
+	if len(nested) < 2 {
+		return s.packLines(block)
+	}
+	nested = append([]codeBlock{{start: block.start, end: opening + 1}}, nested...)
+	return s.pack(nested, block.symbol)
+}
+
+// pack joins consecutive blocks into chunks up to the size, so that small statements and declarations travel
+// together. A chunk never holds more than one named block.
+func (s *codeSplitter) pack(blocks []codeBlock, parent string) ([]chunk, error) {
+	res := make([]chunk, 0)
+	var current *codeBlock
+	currentNamed := false
+	for _, block := range blocks {
+		named := block.symbol != ""
+		block.symbol = qualifySymbol(parent, block.symbol)
+		if current != nil && !(currentNamed && named) {
+			joined := codeBlock{start: current.start, end: block.end, symbol: current.symbol}
+			if named {
+				joined.symbol = block.symbol
+			}
+			if s.length(s.text(joined)) <= s.size {
+				current, currentNamed = &joined, currentNamed || named
+				continue
+			}
+		}
+		if current != nil {
+			chunks, err := s.fit(*current)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, chunks...)
+		}
+		current, currentNamed = &block, named
+	}
+	if current != nil {
+		chunks, err := s.fit(*current)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, chunks...)
+	}
+	return res, nil
+}
+
+func qualifySymbol(parent string, symbol string) string {
+	switch {
+	case parent == "":
+		return symbol
+	case symbol == "":
+		return parent
+	}
+	return parent + "." + symbol
+}
+
+// packLines splits a block with no nested blocks by lines, up to the size. Lines longer than a chunk, as found in
+// minified code, are split on their own.
+func (s *codeSplitter) packLines(block codeBlock) ([]chunk, error) {
+	res := make([]chunk, 0)
+	start := block.start
+	for i := block.start; i < block.end; i++ {
+		line := codeBlock{start: i, end: i + 1, symbol: block.symbol}
+		if s.length(s.text(line)) > s.size {
+			if start < i {
+				res = append(res, s.chunk(codeBlock{start: start, end: i, symbol: block.symbol}))
+			}
+			parts, err := s.fallback.SplitText(s.lines[i])
+			if err != nil {
+				return nil, err
+			}
+			for _, part := range parts {
+				res = append(res, chunk{text: s.header + part, symbol: block.symbol, startLine: i + 1,
+					endLine: i + 1})
+			}
+			start = i + 1
+			continue
+		}
+		if start < i && s.length(s.text(codeBlock{start: start, end: i + 1})) > s.size {
+			res = append(res, s.chunk(codeBlock{start: start, end: i, symbol: block.symbol}))
+			start = i
+		}
+	}
+	if start < block.end {
+		res = append(res, s.chunk(codeBlock{start: start, end: block.end, symbol: block.symbol}))
+	}
+	return res, nil
+}
+
+func (s *codeSplitter) text(block codeBlock) string {
+	return s.header + strings.Join(s.lines[block.start:block.end], "\n")
+}
+
+func (s *codeSplitter) chunk(block codeBlock) chunk {
+	// Leading and trailing blank lines are left out of the chunk and of its range
+	for block.start < block.end-1 && strings.TrimSpace(s.lines[block.start]) == "" {
+		block.start++
+	}
+	for block.end > block.start+1 && strings.TrimSpace(s.lines[block.end-1]) == "" {
+		block.end--
+	}
+	return chunk{text: s.text(block), symbol: block.symbol, startLine: block.start + 1, endLine: block.end}
+}
+
+// blocks splits the lines in [start, end) where a new block begins at their outermost level. The outermost level is
+// given by the nesting of brackets and, at equal nesting, by the indentation, so that both brace and indentation
+// based languages are covered. Comments, annotations and decorators go with the block they precede.
+func (s *codeSplitter) blocks(start int, end int) []codeBlock {
+	depths := s.depths(start, end)
+	candidate := func(i int) bool {
+		line := strings.TrimSpace(s.lines[i])
+		if line == "" || codeTrivia(line) || strings.ContainsAny(line[:1], ")]}") {
+			return false
+		}
+		word, _, _ := strings.Cut(strings.TrimLeft(line, "}) "), " ")
+		return !slices.Contains(codeContinuations, strings.TrimRight(word, ":{(;"))
+	}
+	minDepth, minIndent := -1, -1
+	for i := start; i < end; i++ {
+		if !candidate(i) {
+			continue
+		}
+		depth, indent := depths[i-start], codeIndent(s.lines[i])
+		if minDepth < 0 || depth < minDepth || (depth == minDepth && indent < minIndent) {
+			minDepth, minIndent = depth, indent
+		}
+	}
+	res := make([]codeBlock, 0)
+	blockStart := start
+	for i := start; i < end; i++ {
+		if !candidate(i) || depths[i-start] != minDepth || codeIndent(s.lines[i]) != minIndent {
+			continue
+		}
+		// The comments right above a block belong to it
+		boundary := i
+		for boundary > blockStart && codeTrivia(strings.TrimSpace(s.lines[boundary-1])) {
+			boundary--
+		}
+		if boundary > blockStart {
+			res = append(res, s.namedBlock(blockStart, boundary))
+			blockStart = boundary
+		}
+	}
+	if blockStart < end {
+		res = append(res, s.namedBlock(blockStart, end))
+	}
+	return res
+}
+
+// namedBlock names a block after the first definition among its lines, skipping the comments above it
+func (s *codeSplitter) namedBlock(start int, end int) codeBlock {
+	block := codeBlock{start: start, end: end}
+	for i := start; i < end; i++ {
+		line := strings.TrimSpace(s.lines[i])
+		if line == "" || codeTrivia(line) {
+			continue
+		}
+		block.symbol = codeSymbol(line)
+		break
+	}
+	return block
+}
+
+// codeSymbol returns the name a line of code defines, if any
+func codeSymbol(line string) string {
+	if match := codeDefinition.FindStringSubmatch(line); match != nil {
+		return match[1]
+	}
+	if match := codeAssignment.FindStringSubmatch(line); match != nil {
+		return match[1]
+	}
+	if match := codeFunction.FindStringSubmatch(line); match != nil && !slices.Contains(codeKeywords, match[1]) &&
+		!slices.Contains(codeKeywords, strings.Fields(line)[0]) {
+		return match[1]
+	}
+	return ""
+}
+
+// codeTrivia tells whether a line holds a comment, an annotation or a decorator only
+func codeTrivia(line string) bool {
+	for _, prefix := range []string{"//", "#", "/*", "*", "--", ";", "@", "'''", `"""`} {
+		if strings.HasPrefix(line, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func codeIndent(line string) int {
+	indent := 0
+	for _, r := range line {
+		switch r {
+		case ' ':
+			indent++
+		case '\t':
+			indent += 4
+		default:
+			return indent
+		}
+	}
+	return indent
+}
+
+// depths returns the nesting of brackets at the start of each line in [start, end). Brackets in comments and in
+// strings closed on the same line are ignored.
+func (s *codeSplitter) depths(start int, end int) []int {
+	depths := make([]int, end-start)
+	depth := 0
+	inComment := false
+	for i := start; i < end; i++ {
+		depths[i-start] = depth
+		line := s.lines[i]
+		for j := 0; j < len(line); j++ {
+			switch {
+			case inComment:
+				if strings.HasPrefix(line[j:], "*/") {
+					inComment = false
+					j++
+				}
+			case strings.HasPrefix(line[j:], "/*"):
+				inComment = true
+				j++
+			case strings.HasPrefix(line[j:], "//"):
+				j = len(line)
+			case line[j] == '"' || line[j] == '\'' || line[j] == '`':
+				if closing := strings.IndexByte(line[j+1:], line[j]); closing >= 0 {
+					j += closing + 1
+				}
+			case strings.IndexByte("{([", line[j]) >= 0:
+				depth++
+			case strings.IndexByte("})]", line[j]) >= 0:
+				depth--
+			}
+		}
+	}
+	return depths
+}
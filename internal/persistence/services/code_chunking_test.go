@@ -0,0 +1,132 @@
+/*
+ * Copyright (C) 2026 Simone Pezzano
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package services
+
+import (
+	"reflect"
+	"testing"
+	"unicode/utf8"
+)
+
+const goSource = `// Package shapes draws shapes
+package shapes
+
+import "fmt"
+
+// Square is a square
+type Square struct {
+	Side int
+}
+
+// Area returns the area
+func (s *Square) Area() int {
+	return s.Side * s.Side
+}
+
+const (
+	A = 1
+	B = 2
+)
+
+func Print() {
+	fmt.Println("square")
+}
+`
+
+const pythonSource = `import os
+
+
+def first():
+    return 1
+
+
+class Second:
+    def method(self):
+        return 2
+
+    def other(self):
+        return 3
+`
+
+// codeChunk is the part of a chunk the code splitter fills
+type codeChunk struct {
+	text      string
+	symbol    string
+	startLine int
+	endLine   int
+}
+
+func TestCodeSplitter(t *testing.T) {
+	header := "// shapes/square.go\npackage shapes\n\n"
+	tests := []struct {
+		name     string
+		document string
+		content  string
+		golang   bool
+		size     int
+		want     []codeChunk
+	}{
+		{name: "go declarations", document: "shapes/square.go", content: goSource, golang: true, size: 200,
+			want: []codeChunk{
+				{header + "// Package shapes draws shapes\npackage shapes", "package shapes", 1, 2},
+				{header + "// Square is a square\ntype Square struct {\n\tSide int\n}", "Square", 6, 9},
+				{header + "// Area returns the area\nfunc (s *Square) Area() int {\n\treturn s.Side * s.Side\n}",
+					"Square.Area", 11, 14},
+				{header + "const (\n\tA = 1\n\tB = 2\n)", "A, B", 16, 19},
+				{header + "func Print() {\n\tfmt.Println(\"square\")\n}", "Print", 21, 23},
+			}},
+		{name: "go that does not parse", document: "broken.go", content: "package broken\n\nfunc Open( {\n}\n",
+			golang: true, size: 200, want: []codeChunk{{"package broken\n\nfunc Open( {\n}", "Open", 1, 4}}},
+		{name: "one named block per chunk", document: "shapes.py", content: pythonSource, size: 200,
+			want: []codeChunk{
+				{"import os\n\n\ndef first():\n    return 1", "first", 1, 5},
+				{"class Second:\n    def method(self):\n        return 2\n\n    def other(self):\n        return 3",
+					"Second", 8, 13},
+			}},
+		{name: "large blocks split by nested blocks", document: "shapes.py", content: pythonSource, size: 40,
+			want: []codeChunk{
+				{"import os\n\n\ndef first():\n    return 1", "first", 1, 5},
+				{"class Second:", "Second", 8, 8},
+				{"    def method(self):\n        return 2", "Second.method", 9, 10},
+				{"    def other(self):\n        return 3", "Second.other", 12, 13},
+			}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			splitter := newCodeSplitter(test.content, test.size, utf8.RuneCountInString,
+				characterSplitter(test.size, 0))
+			var chunks []chunk
+			var err error
+			if test.golang {
+				chunks, err = splitter.splitGo(test.document, test.content)
+			} else {
+				chunks, err = splitter.split()
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := make([]codeChunk, len(chunks))
+			for i, item := range chunks {
+				got[i] = codeChunk{item.text, item.symbol, item.startLine, item.endLine}
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
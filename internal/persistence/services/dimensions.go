@@ -0,0 +1,109 @@
+/*
+ * Copyright (C) 2026 Simone Pezzano
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/theirish81/meta/internal/config"
+	"github.com/theirish81/meta/internal/persistence/connection"
+	"gorm.io/gorm"
+)
+
+// knownDimensions lists the native dimension of popular embedding models, so we don't need to probe them
+var knownDimensions = map[string]int{
+	"qwen3-embedding":                 4096,
+	"qwen3-embedding:0.6b":            1024,
+	"qwen3-embedding:4b":              2560,
+	"qwen3-embedding:8b":              4096,
+	"nomic-embed-text":                768,
+	"mxbai-embed-large":               1024,
+	"all-minilm":                      384,
+	"bge-m3":                          1024,
+	"embeddinggemma":                  768,
+	"gemini-embedding-001":            3072,
+	"text-embedding-005":              768,
+	"text-multilingual-embedding-002": 768,
+	"text-embedding-3-small":          1536,
+	"text-embedding-3-large":          3072,
+	"text-embedding-ada-002":          1536,
+}
+
+// resolveDimensions returns the dimension of the vectors produced by the configured model. The explicit configuration
+// wins, then the known models, and as a last resort the provider is asked to embed a probe text.
+func resolveDimensions(ctx context.Context, service EmbeddingService) (int, error) {
+	if config.Instance.EmbeddingDimensions > 0 {
+		return config.Instance.EmbeddingDimensions, nil
+	}
+	if dimensions, ok := knownDimensions[strings.TrimSuffix(config.Instance.EmbeddingModel, ":latest")]; ok {
+		return dimensions, nil
+	}
+	embeddings, err := service.ExtractEmbeddings(ctx, PurposeDocument, []string{"dimension probe"})
+	if err != nil {
+		return 0, fmt.Errorf("could not determine the dimension of %s: %w", config.Instance.EmbeddingModel, err)
+	}
+	return len(embeddings[0].Vector), nil
+}
+
+// ensureVectorDimensions sizes the embedding column of a table to the given dimension. Rows holding vectors of
+// a different dimension make it refuse, unless they are zero-padded vectors that can be truncated without loss.
+func ensureVectorDimensions(ctx context.Context, conn *connection.Connection, table string, dimensions int) error {
+	db := conn.WithContext(ctx)
+	stored, err := storedDimensions(db, table)
+	if err != nil {
+		return err
+	}
+	if stored == dimensions {
+		return nil
+	}
+	var mismatched, lossy int64
+	err = db.Raw(`SELECT count(*), count(*) FILTER (WHERE CASE
+			WHEN vector_dims(embedding) < ? THEN true
+			ELSE vector_norm(subvector(embedding, ? + 1, vector_dims(embedding) - ?)) > 0 END)
+		FROM `+table+` WHERE vector_dims(embedding) <> ?`, dimensions, dimensions, dimensions, dimensions).
+		Row().Scan(&mismatched, &lossy)
+	if err != nil {
+		return err
+	}
+	if lossy > 0 {
+		return fmt.Errorf("%w: %s holds %d embeddings that are not %d-dimensional as produced by %s. Configure the "+
+			"model that produced them or run `meta reindex`", ErrDimensionMismatch, table, lossy, dimensions,
+			config.Instance.EmbeddingModel)
+	}
+	if mismatched > 0 {
+		log.Printf("truncating %d zero-padded embeddings in %s to %d dimensions", mismatched, table, dimensions)
+	}
+	// The index is built for the old dimension. ensureVectorIndex creates it anew
+	if err = dropVectorIndex(db, table, false); err != nil {
+		return err
+	}
+	return db.Exec(fmt.Sprintf(`ALTER TABLE %s ALTER COLUMN embedding TYPE vector(%d) USING CASE
+			WHEN vector_dims(embedding) = %d THEN embedding
+			ELSE subvector(embedding, 1, %d) END`, table, dimensions, dimensions, dimensions)).Error
+}
+
+// storedDimensions returns the dimension the embedding column of a table is constrained to, or -1 when unconstrained
+func storedDimensions(db *gorm.DB, table string) (int, error) {
+	var stored int
+	err := db.Raw("SELECT atttypmod FROM pg_attribute WHERE attrelid = ?::regclass AND attname = 'embedding'",
+		table).Scan(&stored).Error
+	return stored, err
+}
@@ -0,0 +1,174 @@
+/*
+ * Copyright (C) 2026 Simone Pezzano
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+	"github.com/theirish81/meta/internal/persistence/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SourceFile is a file a document was uploaded as
+type SourceFile struct {
+	Name      string
+	MediaType string
+	Data      []byte
+}
+
+// hash fingerprints the file, returning an empty string when there is none
+func (f *SourceFile) hash() string {
+	if f == nil {
+		return ""
+	}
+	return contentHash(string(f.Data))
+}
+
+// storeContent keeps the content a document was submitted with, replacing the previous one
+func storeContent(tx *gorm.DB, documentID uuid.UUID, content string, file *SourceFile) error {
+	row := domain.DocumentContent{
+		DocumentID: documentID,
+		Text:       content,
+		FileHash:   file.hash(),
+	}
+	if file != nil {
+		row.FileName = &file.Name
+		row.MediaType = &file.MediaType
+		row.File = file.Data
+	}
+	return tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(&row).Error
+}
+
+// keepContent stores the content of a document submitted again with the same text, unless it was already stored
+// along with the same file
+func (s *KnowledgeBaseService) keepContent(ctx context.Context, document domain.Document, content string,
+	file *SourceFile) error {
+	stored := make([]domain.DocumentContent, 0)
+	err := s.conn.WithContext(ctx).Select("document_id, file_hash").Where("document_id = ?", document.ID).
+		Find(&stored).Error
+	if err != nil || (len(stored) > 0 && stored[0].FileHash == file.hash()) {
+		return err
+	}
+	return s.conn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockDocument(tx, document.IdentityID, document.Memory, document.Name); err != nil {
+			return err
+		}
+		return storeContent(tx, document.ID, content, file)
+	})
+}
+
+// GetDocument returns a document along with the content it was last submitted with. The file it was uploaded as is
+// only loaded when withFile is set.
+func (s *KnowledgeBaseService) GetDocument(ctx context.Context, ownerID string, memory string, name string,
+	withFile bool) (domain.Document, domain.DocumentContent, error) {
+	db := s.conn.WithContext(ctx)
+	document, err := findDocument(db, ownerID, memory, name)
+	if err != nil {
+		return domain.Document{}, domain.DocumentContent{}, err
+	}
+	if document == nil {
+		return domain.Document{}, domain.DocumentContent{}, fmt.Errorf("%w: %s", ErrDocumentNotFound, name)
+	}
+	query := db.Where("document_id = ?", document.ID)
+	if !withFile {
+		query = query.Omit("file")
+	}
+	contents := make([]domain.DocumentContent, 0)
+	if err = query.Limit(1).Find(&contents).Error; err != nil {
+		return *document, domain.DocumentContent{}, err
+	}
+	if len(contents) == 0 {
+		return *document, domain.DocumentContent{}, fmt.Errorf("%w: %s was recorded before the content of "+
+			"documents was kept, submit it again to read it back", ErrDocumentNotFound, name)
+	}
+	return *document, contents[0], nil
+}
+
+// ContentRange selects part of the text of a document, either by bytes or by lines. Unset bounds extend to the start
+// or the end of the text.
+type ContentRange struct {
+	// StartByte and EndByte bound a range of bytes, from 0 and exclusive of the end. Bounds falling within a
+	// character are widened to include it
+	StartByte *int
+	EndByte   *int
+	// StartLine and EndLine bound a range of lines, from 1 and inclusive of the end
+	StartLine *int
+	EndLine   *int
+}
+
+// ContentSlice is part of the text of a document, with its bounds and the size of the whole text
+type ContentSlice struct {
+	Text       string
+	StartByte  int
+	EndByte    int
+	StartLine  int
+	EndLine    int
+	TotalBytes int
+	TotalLines int
+}
+
+// Slice returns the part of a text in the range
+func (r ContentRange) Slice(text string) (ContentSlice, error) {
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	res := ContentSlice{TotalBytes: len(text), TotalLines: len(lines)}
+	byBytes := r.StartByte != nil || r.EndByte != nil
+	byLines := r.StartLine != nil || r.EndLine != nil
+	switch {
+	case byBytes && byLines:
+		return res, fmt.Errorf("%w: select either bytes or lines", ErrInvalidRange)
+	case byLines:
+		start, end := lo.FromPtrOr(r.StartLine, 1), min(lo.FromPtrOr(r.EndLine, len(lines)), len(lines))
+		if start < 1 || (r.EndLine != nil && *r.EndLine < start) {
+			return res, fmt.Errorf("%w: lines start from 1 and end_line cannot precede start_line", ErrInvalidRange)
+		}
+		start = min(start, len(lines)+1)
+		end = max(end, start-1)
+		res.StartByte = len(strings.Join(lines[:start-1], ""))
+		res.EndByte = res.StartByte + len(strings.Join(lines[start-1:end], ""))
+	default:
+		start, end := lo.FromPtrOr(r.StartByte, 0), min(lo.FromPtrOr(r.EndByte, len(text)), len(text))
+		if start < 0 || (r.EndByte != nil && *r.EndByte < start) {
+			return res, fmt.Errorf("%w: bytes start from 0 and end_byte cannot precede start_byte", ErrInvalidRange)
+		}
+		start = min(start, len(text))
+		end = max(end, start)
+		for start > 0 && start < len(text) && !utf8.RuneStart(text[start]) {
+			start--
+		}
+		for end < len(text) && !utf8.RuneStart(text[end]) {
+			end++
+		}
+		res.StartByte, res.EndByte = start, end
+	}
+	res.Text = text[res.StartByte:res.EndByte]
+	res.StartLine = 1 + strings.Count(text[:res.StartByte], "\n")
+	res.EndLine = res.StartLine + strings.Count(strings.TrimSuffix(res.Text, "\n"), "\n")
+	if res.Text == "" {
+		res.EndLine = res.StartLine - 1
+	}
+	return res, nil
+}
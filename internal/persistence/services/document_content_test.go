@@ -0,0 +1,87 @@
+/*
+ * Copyright (C) 2026 Simone Pezzano
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/samber/lo"
+)
+
+func TestContentRangeSlice(t *testing.T) {
+	text := "first\nsecönd\nthird\n"
+	tests := []struct {
+		name    string
+		text    string
+		r       ContentRange
+		want    ContentSlice
+		wantErr error
+	}{
+		{name: "whole text", text: text,
+			want: ContentSlice{Text: text, EndByte: 20, StartLine: 1, EndLine: 3, TotalBytes: 20, TotalLines: 3}},
+		{name: "single line", text: text, r: ContentRange{StartLine: lo.ToPtr(2), EndLine: lo.ToPtr(2)},
+			want: ContentSlice{Text: "secönd\n", StartByte: 6, EndByte: 14, StartLine: 2, EndLine: 2, TotalBytes: 20,
+				TotalLines: 3}},
+		{name: "lines to the end", text: text, r: ContentRange{StartLine: lo.ToPtr(2)},
+			want: ContentSlice{Text: "secönd\nthird\n", StartByte: 6, EndByte: 20, StartLine: 2, EndLine: 3,
+				TotalBytes: 20, TotalLines: 3}},
+		{name: "lines past the end", text: text, r: ContentRange{StartLine: lo.ToPtr(3), EndLine: lo.ToPtr(10)},
+			want: ContentSlice{Text: "third\n", StartByte: 14, EndByte: 20, StartLine: 3, EndLine: 3, TotalBytes: 20,
+				TotalLines: 3}},
+		{name: "lines after the text", text: text, r: ContentRange{StartLine: lo.ToPtr(5)},
+			want: ContentSlice{StartByte: 20, EndByte: 20, StartLine: 4, EndLine: 3, TotalBytes: 20, TotalLines: 3}},
+		{name: "last line without newline", text: "a\nb", r: ContentRange{StartLine: lo.ToPtr(2)},
+			want: ContentSlice{Text: "b", StartByte: 2, EndByte: 3, StartLine: 2, EndLine: 2, TotalBytes: 3,
+				TotalLines: 2}},
+		{name: "bytes", text: text, r: ContentRange{StartByte: lo.ToPtr(0), EndByte: lo.ToPtr(5)},
+			want: ContentSlice{Text: "first", EndByte: 5, StartLine: 1, EndLine: 1, TotalBytes: 20, TotalLines: 3}},
+		{name: "bytes widened to whole characters", text: text,
+			r: ContentRange{StartByte: lo.ToPtr(10), EndByte: lo.ToPtr(10)},
+			want: ContentSlice{Text: "ö", StartByte: 9, EndByte: 11, StartLine: 2, EndLine: 2, TotalBytes: 20,
+				TotalLines: 3}},
+		{name: "bytes past the end", text: text, r: ContentRange{StartByte: lo.ToPtr(14), EndByte: lo.ToPtr(100)},
+			want: ContentSlice{Text: "third\n", StartByte: 14, EndByte: 20, StartLine: 3, EndLine: 3, TotalBytes: 20,
+				TotalLines: 3}},
+		{name: "bytes and lines", text: text, r: ContentRange{StartByte: lo.ToPtr(0), EndLine: lo.ToPtr(1)},
+			wantErr: ErrInvalidRange},
+		{name: "negative byte", text: text, r: ContentRange{StartByte: lo.ToPtr(-1)}, wantErr: ErrInvalidRange},
+		{name: "end byte before start", text: text, r: ContentRange{StartByte: lo.ToPtr(5), EndByte: lo.ToPtr(4)},
+			wantErr: ErrInvalidRange},
+		{name: "line zero", text: text, r: ContentRange{StartLine: lo.ToPtr(0)}, wantErr: ErrInvalidRange},
+		{name: "end line before start", text: text, r: ContentRange{StartLine: lo.ToPtr(3), EndLine: lo.ToPtr(2)},
+			wantErr: ErrInvalidRange},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := test.r.Slice(test.text)
+			if test.wantErr != nil {
+				if !errors.Is(err, test.wantErr) {
+					t.Fatalf("got error %v, want %v", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != test.want {
+				t.Errorf("got %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+/*
+ * Copyright (C) 2026 Simone Pezzano
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"reflect"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+	"github.com/theirish81/meta/internal/config"
+	"github.com/theirish81/meta/internal/persistence/connection"
+	"github.com/theirish81/meta/internal/persistence/domain"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// contentHash fingerprints the content of a document, to tell whether a re-upload changes it
+func contentHash(content string) string {
+	hash := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(hash[:])
+}
+
+// sameTags tells whether two lists hold the same tags, in whatever order
+func sameTags(a []string, b []string) bool {
+	a, b = slices.Clone(a), slices.Clone(b)
+	slices.Sort(a)
+	slices.Sort(b)
+	return slices.Equal(slices.Compact(a), slices.Compact(b))
+}
+
+// storedChunk is the part of a stored chunk that tells whether it can be kept when its document is recorded again
+type storedChunk struct {
+	ID          uuid.UUID
+	ContentHash string
+	Symbol      *string
+	StartLine   *int
+	EndLine     *int
+	Ordinal     *int
+	StartOffset *int
+	EndOffset   *int
+	Headings    datatypes.JSONSlice[string]
+}
+
+// samePosition tells whether a stored chunk is where the new row of its content places it
+func (c storedChunk) samePosition(row domain.KnowledgeChunk) bool {
+	return reflect.DeepEqual(c.Symbol, row.Symbol) && reflect.DeepEqual(c.StartLine, row.StartLine) &&
+		reflect.DeepEqual(c.EndLine, row.EndLine) && reflect.DeepEqual(c.Ordinal, row.Ordinal) &&
+		reflect.DeepEqual(c.StartOffset, row.StartOffset) && reflect.DeepEqual(c.EndOffset, row.EndOffset) &&
+		slices.Equal(c.Headings, row.Headings)
+}
+
+// matchChunks pairs the new chunks of a document with the stored chunks of the same content, each stored chunk being
+// used once. It returns the stored chunk to keep for each new chunk, or nil when the new chunk needs to be embedded.
+func matchChunks(stored []storedChunk, chunks []chunk) []*storedChunk {
+	byHash := make(map[string][]*storedChunk)
+	for i := range stored {
+		byHash[stored[i].ContentHash] = append(byHash[stored[i].ContentHash], &stored[i])
+	}
+	kept := make([]*storedChunk, len(chunks))
+	for i, item := range chunks {
+		if candidates := byHash[contentHash(item.text)]; len(candidates) > 0 {
+			kept[i] = candidates[0]
+			byHash[contentHash(item.text)] = candidates[1:]
+		}
+	}
+	return kept
+}
+
+// newKnowledgeChunk returns the row of a new chunk of a document, but for its embedding
+func newKnowledgeChunk(ownerID string, memory string, document string, tags []string,
+	settings domain.ChunkingSettings, item chunk) domain.KnowledgeChunk {
+	kc := domain.KnowledgeChunk{
+		Memory:            memory,
+		Document:          document,
+		Tags:              tags,
+		Chunk:             item.text,
+		ContentHash:       contentHash(item.text),
+		EmbeddingProvider: config.Instance.EmbeddingService,
+		EmbeddingModel:    config.Instance.EmbeddingModel,
+		IdentityID:        ownerID,
+		Chunking:          datatypes.NewJSONType(settings),
+		Symbol:            lo.EmptyableToPtr(item.symbol),
+		Ordinal:           lo.ToPtr(item.ordinal),
+		StartOffset:       item.start,
+		EndOffset:         item.end,
+		Headings:          lo.CoalesceSliceOrEmpty(item.headings),
+	}
+	if item.startLine > 0 {
+		kc.StartLine = lo.ToPtr(item.startLine)
+		kc.EndLine = lo.ToPtr(item.endLine)
+	}
+	return kc
+}
+
+// backfillChunkHashes fingerprints the chunks that were recorded before their content hash was tracked
+func backfillChunkHashes(ctx context.Context, conn *connection.Connection) error {
+	return conn.WithContext(ctx).Exec(`UPDATE knowledge_chunks SET content_hash = encode(sha256(convert_to(chunk,
+		'UTF8')), 'hex') WHERE content_hash = ''`).Error
+}
+
+// hasUnpositionedChunks tells whether a document has chunks recorded before their position was tracked
+func hasUnpositionedChunks(tx *gorm.DB, ownerID string, memory string, document string) (bool, error) {
+	var count int64
+	err := tx.Model(&domain.KnowledgeChunk{}).Where("identity_id = ? AND memory = ? AND document = ?", ownerID, memory,
+		document).Where("ordinal IS NULL").Count(&count).Error
+	return count > 0, err
+}
+
+// findDocument returns the document of a memory with the given name, if any
+func findDocument(tx *gorm.DB, ownerID string, memory string, name string) (*domain.Document, error) {
+	documents := make([]domain.Document, 0)
+	err := tx.Where(&domain.Document{IdentityID: ownerID, Memory: memory, Name: name}).Limit(1).
+		Find(&documents).Error
+	if err != nil || len(documents) == 0 {
+		return nil, err
+	}
+	return &documents[0], nil
+}
+
+// backfillDocuments registers the documents that were recorded before the registry existed, from their chunks. Their
+// content hash and size are unknown, so their next upload is recorded in full.
+func backfillDocuments(ctx context.Context, conn *connection.Connection) error {
+	res := conn.WithContext(ctx).Exec(`INSERT INTO documents (memory, name, identity_id, tags, chunk_count, chunking,
+		created_at, updated_at)
+		SELECT c.memory, c.document, c.identity_id, (array_agg(c.tags))[1], count(*), (array_agg(c.chunking))[1],
+			now(), now()
+		FROM knowledge_chunks c
+		WHERE NOT EXISTS (SELECT 1 FROM documents d WHERE d.identity_id = c.identity_id AND d.memory = c.memory
+			AND d.name = c.document)
+		GROUP BY c.identity_id, c.memory, c.document`)
+	if res.RowsAffected > 0 {
+		log.Printf("registered %d documents recorded before the document registry", res.RowsAffected)
+	}
+	return res.Error
+}
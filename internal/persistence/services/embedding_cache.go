@@ -0,0 +1,200 @@
+/*
+ * Copyright (C) 2026 Simone Pezzano
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log"
+	"sync/atomic"
+
+	"github.com/pgvector/pgvector-go"
+	"github.com/samber/lo"
+	"github.com/theirish81/meta/internal/config"
+	"github.com/theirish81/meta/internal/persistence/connection"
+	"github.com/theirish81/meta/internal/persistence/domain"
+	"gorm.io/gorm/clause"
+)
+
+// cacheKeyVersion is bumped whenever the keys of cached vectors change meaning, so that the vectors cached under the
+// old keys are discarded
+const cacheKeyVersion = 1
+
+type EmbeddingCacheService struct {
+	conn *connection.Connection
+}
+
+func NewEmbeddingCacheService() *EmbeddingCacheService {
+	return &EmbeddingCacheService{
+		conn: connection.Conn,
+	}
+}
+
+func (s *EmbeddingCacheService) InitTables(ctx context.Context) error {
+	if err := s.conn.WithContext(ctx).AutoMigrate(&domain.EmbeddingCacheEntry{}); err != nil {
+		return err
+	}
+	res := s.conn.WithContext(ctx).Where("key_version <> ?", cacheKeyVersion).Delete(&domain.EmbeddingCacheEntry{})
+	if res.RowsAffected > 0 {
+		log.Printf("%d embeddings cached under outdated keys deleted", res.RowsAffected)
+	}
+	return res.Error
+}
+
+func (s *EmbeddingCacheService) Lookup(ctx context.Context, provider string, model string,
+	hashes []string) (map[string]pgvector.Vector, error) {
+	entries := make([]domain.EmbeddingCacheEntry, 0)
+	err := s.conn.WithContext(ctx).Where("provider = ? AND model = ? AND hash IN ? AND key_version = ?", provider, model, hashes,
+		cacheKeyVersion).
+		Find(&entries).Error
+	return lo.SliceToMap(entries, func(item domain.EmbeddingCacheEntry) (string, pgvector.Vector) {
+		return item.Hash, item.Vector
+	}), err
+}
+
+func (s *EmbeddingCacheService) Store(ctx context.Context, entries []domain.EmbeddingCacheEntry) error {
+	return s.conn.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "provider"}, {Name: "model"}, {Name: "hash"}},
+		DoUpdates: clause.AssignmentColumns([]string{"vector", "key_version", "created_at"}),
+	}).CreateInBatches(&entries, 100).Error
+}
+
+// Prune deletes the cached vectors of a model, of a provider, or of a model of a provider
+func (s *EmbeddingCacheService) Prune(ctx context.Context, provider string, model string) (int64, error) {
+	if provider == "" && model == "" {
+		return 0, errors.New("a provider or a model is required")
+	}
+	tx := s.conn.WithContext(ctx)
+	if model != "" {
+		tx = tx.Where("model = ?", model)
+	}
+	if provider != "" {
+		tx = tx.Where("provider = ?", provider)
+	}
+	res := tx.Delete(&domain.EmbeddingCacheEntry{})
+	return res.RowsAffected, res.Error
+}
+
+// PruneExcept deletes the cached vectors of every model but the given one
+func (s *EmbeddingCacheService) PruneExcept(ctx context.Context, provider string, model string) (int64, error) {
+	res := s.conn.WithContext(ctx).Where("NOT (provider = ? AND model = ?)", provider, model).
+		Delete(&domain.EmbeddingCacheEntry{})
+	return res.RowsAffected, res.Error
+}
+
+type CacheStats struct {
+	Hits   int64 `json:"hits"`
+	Misses int64 `json:"misses"`
+}
+
+// CachedEmbeddingService decorates an EmbeddingService, storing every vector keyed by provider, model and hash of the
+// text, so that unchanged texts are never embedded twice. Cache failures are logged and never fail the extraction.
+type CachedEmbeddingService struct {
+	service  EmbeddingService
+	cache    *EmbeddingCacheService
+	provider string
+	model    string
+	hits     atomic.Int64
+	misses   atomic.Int64
+}
+
+func NewCachedEmbeddingService(service EmbeddingService, cache *EmbeddingCacheService, provider string,
+	model string) *CachedEmbeddingService {
+	return &CachedEmbeddingService{
+		service:  service,
+		cache:    cache,
+		provider: provider,
+		model:    model,
+	}
+}
+
+func (s *CachedEmbeddingService) ExtractEmbeddings(ctx context.Context, purpose EmbeddingPurpose,
+	input []string) ([]Embedding, error) {
+	hashes := lo.Map(input, func(item string, _ int) string {
+		return cacheHash(purpose, item)
+	})
+	cached, err := s.cache.Lookup(ctx, s.provider, s.model, lo.Uniq(hashes))
+	if err != nil {
+		log.Println("embedding cache lookup failed:", err)
+		cached = map[string]pgvector.Vector{}
+	}
+	// Vectors cached with a different dimension, e.g. before the dimension was changed, are treated as misses
+	for hash, vector := range cached {
+		if Services.EmbeddingDimensions > 0 && len(vector.Slice()) != Services.EmbeddingDimensions {
+			delete(cached, hash)
+		}
+	}
+	missing := make([]string, 0)
+	missingHashes := make([]string, 0)
+	requested := make(map[string]bool)
+	for i, text := range input {
+		if _, ok := cached[hashes[i]]; !ok && !requested[hashes[i]] {
+			missing = append(missing, text)
+			missingHashes = append(missingHashes, hashes[i])
+			requested[hashes[i]] = true
+		}
+	}
+	s.hits.Add(int64(len(input) - len(missing)))
+	s.misses.Add(int64(len(missing)))
+	if len(missing) > 0 {
+		embeddings, err := s.service.ExtractEmbeddings(ctx, purpose, missing)
+		if err != nil {
+			return nil, err
+		}
+		entries := make([]domain.EmbeddingCacheEntry, len(embeddings))
+		for i, embedding := range embeddings {
+			cached[missingHashes[i]] = pgvector.NewVector(embedding.Vector)
+			entries[i] = domain.EmbeddingCacheEntry{
+				Provider:   s.provider,
+				Model:      s.model,
+				Hash:       missingHashes[i],
+				Vector:     cached[missingHashes[i]],
+				KeyVersion: cacheKeyVersion,
+			}
+		}
+		if err := s.cache.Store(ctx, entries); err != nil {
+			log.Println("embedding cache store failed:", err)
+		}
+	}
+	embeddings := make([]Embedding, len(input))
+	for i, text := range input {
+		embeddings[i] = Embedding{Text: text, Vector: cached[hashes[i]].Slice()}
+	}
+	return embeddings, nil
+}
+
+func (s *CachedEmbeddingService) Stats() CacheStats {
+	return CacheStats{Hits: s.hits.Load(), Misses: s.misses.Load()}
+}
+
+// cacheHash keys the vector of a text. Queries are embedded differently from documents, and depend on the query
+// template, so the purpose and the template are part of the key.
+func cacheHash(purpose EmbeddingPurpose, text string) string {
+	template := ""
+	if purpose == PurposeQuery {
+		template = config.Instance.EmbeddingQueryTemplate
+	}
+	return hashText(string(purpose) + "\x00" + template + "\x00" + text)
+}
+
+func hashText(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,99 @@
+/*
+ * Copyright (C) 2026 Simone Pezzano
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+)
+
+var (
+	// ErrProviderUnavailable is returned when the embedding provider cannot be reached or reports a transient failure
+	ErrProviderUnavailable = errors.New("embedding provider unavailable")
+	// ErrDimensionMismatch is returned when the embedding provider returns vectors of an unexpected size
+	ErrDimensionMismatch = errors.New("embedding dimension mismatch")
+	// ErrModelNotFound is returned when the embedding provider does not know the configured model
+	ErrModelNotFound = errors.New("embedding model not found")
+	// ErrInvalidEmbedding is returned when the embedding provider returns an unusable response
+	ErrInvalidEmbedding = errors.New("invalid embedding response")
+	// ErrInvalidSearch is returned when the options of a search are out of range
+	ErrInvalidSearch = errors.New("invalid search")
+	// ErrInvalidChunking is returned when chunking settings are out of range
+	ErrInvalidChunking = errors.New("invalid chunking settings")
+	// ErrDocumentChanged is returned when a document is modified by someone else while being recorded
+	ErrDocumentChanged = errors.New("document changed concurrently")
+	// ErrDocumentNotFound is returned when a document, or its content, is not stored
+	ErrDocumentNotFound = errors.New("document not found")
+	// ErrChunkNotFound is returned when a knowledge chunk is not stored
+	ErrChunkNotFound = errors.New("chunk not found")
+	// ErrTooManyChunks is returned when more knowledge chunks are requested at once than a search can return
+	ErrTooManyChunks = errors.New("too many chunks")
+	// ErrInvalidRange is returned when the range of a document to read is out of bounds
+	ErrInvalidRange = errors.New("invalid range")
+)
+
+// statusError turns a non-200 provider response into one of the typed errors
+func statusError(provider string, statusCode int, body []byte) error {
+	switch {
+	case statusCode == http.StatusNotFound:
+		return fmt.Errorf("%w: %s returned %d: %s", ErrModelNotFound, provider, statusCode, string(body))
+	case statusCode == http.StatusTooManyRequests || statusCode >= 500:
+		return fmt.Errorf("%w: %s returned %d: %s", ErrProviderUnavailable, provider, statusCode, string(body))
+	default:
+		return fmt.Errorf("%w: %s returned %d: %s", ErrInvalidEmbedding, provider, statusCode, string(body))
+	}
+}
+
+// transportError classifies a failed provider call. Cancellations coming from the caller are returned as they are,
+// while everything else, including the per-call timeout, means the provider is not responding.
+func transportError(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return fmt.Errorf("%w: %w", ErrProviderUnavailable, err)
+}
+
+// validateVectors verifies that the provider returned one usable vector per input, all of the expected dimension
+func validateVectors(provider string, vectors [][]float32, inputCount int) error {
+	if len(vectors) != inputCount {
+		return fmt.Errorf("%w: %s returned %d vectors for %d inputs", ErrInvalidEmbedding, provider, len(vectors),
+			inputCount)
+	}
+	// Until the dimension is resolved at startup, vectors only need to be consistent with each other
+	expected := Services.EmbeddingDimensions
+	for i, vector := range vectors {
+		if expected == 0 && i == 0 {
+			expected = len(vector)
+		}
+		if len(vector) != expected {
+			return fmt.Errorf("%w: %s returned a vector of %d dimensions, expected %d", ErrDimensionMismatch,
+				provider, len(vector), expected)
+		}
+		var norm float64
+		for _, x := range vector {
+			norm += float64(x) * float64(x)
+		}
+		if norm == 0 || math.IsNaN(norm) || math.IsInf(norm, 0) {
+			return fmt.Errorf("%w: %s returned a zero or non-finite vector", ErrInvalidEmbedding, provider)
+		}
+	}
+	return nil
+}
@@ -19,6 +19,7 @@ package services
 
 import (
 	"context"
+	"errors"
 	"os"
 
 	"cloud.google.com/go/auth/credentials"
@@ -55,24 +56,48 @@ func NewGeminiService() (*GeminiService, error) {
 	return &GeminiService{client: client}, nil
 }
 
-func (s *GeminiService) ExtractEmbeddings(input []string) ([]Embedding, error) {
+func (s *GeminiService) ExtractEmbeddings(ctx context.Context, purpose EmbeddingPurpose,
+	input []string) ([]Embedding, error) {
 	contents := lo.Map[string, *genai.Content](input, func(item string, index int) *genai.Content {
 		return genai.NewContentFromText(item, "")
 	})
-	result, err := s.client.Models.EmbedContent(context.Background(),
+	callCtx, cancel := context.WithTimeout(ctx, config.Instance.EmbeddingTimeout)
+	defer cancel()
+	embedConfig := &genai.EmbedContentConfig{TaskType: "RETRIEVAL_DOCUMENT"}
+	if purpose == PurposeQuery {
+		embedConfig.TaskType = "RETRIEVAL_QUERY"
+	}
+	if config.Instance.EmbeddingDimensions > 0 {
+		embedConfig.OutputDimensionality = genai.Ptr(int32(config.Instance.EmbeddingDimensions))
+	}
+	result, err := s.client.Models.EmbedContent(callCtx,
 		config.Instance.EmbeddingModel,
 		contents,
-		nil,
+		embedConfig,
 	)
 	if err != nil {
+		return nil, geminiError(ctx, err)
+	}
+	vectors := lo.Map(result.Embeddings, func(item *genai.ContentEmbedding, _ int) []float32 {
+		return item.Values
+	})
+	if err := validateVectors("gemini", vectors, len(input)); err != nil {
 		return nil, err
 	}
 	embeddings := make([]Embedding, len(input))
 	for i := 0; i < len(input); i++ {
 		embeddings[i] = Embedding{
 			Text:   input[i],
-			Vector: result.Embeddings[i].Values,
+			Vector: vectors[i],
 		}
 	}
 	return embeddings, nil
 }
+
+func geminiError(ctx context.Context, err error) error {
+	var apiErr genai.APIError
+	if errors.As(err, &apiErr) {
+		return statusError("gemini", apiErr.Code, []byte(apiErr.Message))
+	}
+	return transportError(ctx, err)
+}
@@ -17,11 +17,19 @@
 
 package services
 
+import "context"
+
 type Embedding struct {
 	Text   string
 	Vector []float32
 }
 
 type EmbeddingService interface {
-	ExtractEmbeddings(input []string) ([]Embedding, error)
+	ExtractEmbeddings(ctx context.Context, purpose EmbeddingPurpose, input []string) ([]Embedding, error)
+}
+
+// Reranker scores how relevant each document is to the query. Higher scores mean more relevant documents, and are
+// comparable within the same call only
+type Reranker interface {
+	Rerank(ctx context.Context, query string, documents []string) ([]float64, error)
 }
@@ -0,0 +1,284 @@
+/*
+ * Copyright (C) 2026 Simone Pezzano
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+	"github.com/theirish81/meta/internal/config"
+	"github.com/theirish81/meta/internal/extraction"
+	"github.com/theirish81/meta/internal/persistence/connection"
+	"github.com/theirish81/meta/internal/persistence/domain"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+const (
+	JobQueued    = "queued"
+	JobRunning   = "running"
+	JobSucceeded = "succeeded"
+	JobFailed    = "failed"
+)
+
+const (
+	// jobRetryDelay is the wait before the second attempt at a failed job. It doubles at every further attempt.
+	jobRetryDelay = 30 * time.Second
+	// jobPruneInterval is how often the jobs finished before the retention period are deleted
+	jobPruneInterval = time.Hour
+)
+
+// JobService records documents in the background. Jobs are stored in Postgres, so that they survive restarts and are
+// shared by every instance: a worker claims one job at a time, skipping the ones locked by other workers, and holds a
+// lease on it until done. The jobs of a document run in the order they were submitted.
+type JobService struct {
+	conn *connection.Connection
+	// wake tells the workers of this instance that a job was just submitted, sparing them the wait for the next poll
+	wake chan struct{}
+}
+
+func NewJobService() *JobService {
+	return &JobService{
+		conn: connection.Conn,
+		wake: make(chan struct{}, 1),
+	}
+}
+
+func (s *JobService) InitTables(ctx context.Context) error {
+	return s.conn.WithContext(ctx).AutoMigrate(&domain.IngestionJob{})
+}
+
+// EnqueueDocument submits a document to be recorded by a worker. Chunking settings out of range are refused right
+// away, rather than by the worker.
+func (s *JobService) EnqueueDocument(ctx context.Context, ownerID string, memory string, document string,
+	tags []string, content string, format extraction.Format, file *SourceFile,
+	chunking *domain.ChunkingSettings) (domain.IngestionJob, error) {
+	memorySettings, err := Services.KnowledgeBaseService.ChunkingSettings(ctx, ownerID, memory)
+	if err != nil {
+		return domain.IngestionJob{}, err
+	}
+	if _, err = resolveChunking(format, &memorySettings, chunking); err != nil {
+		return domain.IngestionJob{}, err
+	}
+	job := domain.IngestionJob{
+		IdentityID: ownerID,
+		Memory:     memory,
+		Document:   document,
+		Tags:       tags,
+		Content:    content,
+		Format:     string(format),
+		State:      JobQueued,
+	}
+	if chunking != nil {
+		job.Chunking = datatypes.NewJSONType(*chunking)
+	}
+	if file != nil {
+		job.FileName = &file.Name
+		job.MediaType = &file.MediaType
+		job.File = file.Data
+	}
+	if err = s.conn.WithContext(ctx).Create(&job).Error; err != nil {
+		return domain.IngestionJob{}, err
+	}
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	return job, nil
+}
+
+// Get returns a job of the owner, without the content of its document
+func (s *JobService) Get(ctx context.Context, ownerID string, id uuid.UUID) (domain.IngestionJob, error) {
+	job := domain.IngestionJob{}
+	err := s.conn.WithContext(ctx).Omit("content", "file").Where("id = ? AND identity_id = ?", id, ownerID).First(&job).Error
+	return job, err
+}
+
+// Start launches the workers of this instance, along with the pruning of old jobs, until the context is done
+func (s *JobService) Start(ctx context.Context, workers int) {
+	if workers == 0 {
+		return
+	}
+	for range workers {
+		go s.work(ctx)
+	}
+	go s.prune(ctx)
+}
+
+func (s *JobService) work(ctx context.Context) {
+	ticker := time.NewTicker(config.Instance.JobPollInterval)
+	defer ticker.Stop()
+	for {
+		job, err := s.claim(ctx)
+		if err != nil {
+			log.Printf("could not claim an ingestion job: %v", err)
+		}
+		if job != nil {
+			s.process(ctx, *job)
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// claim takes the lease on the next job due, if any. Jobs whose lease expired are due again, as their worker stopped
+// responding. A job waits for the previous jobs of the same document to finish.
+func (s *JobService) claim(ctx context.Context) (*domain.IngestionJob, error) {
+	jobs := make([]domain.IngestionJob, 0)
+	err := s.conn.WithContext(ctx).Raw(`UPDATE ingestion_jobs SET state = ?, attempts = attempts + 1, lease_token = ?,
+		lease_expires_at = now() + make_interval(secs => ?), started_at = COALESCE(started_at, now()),
+		updated_at = now()
+		WHERE id = (SELECT j.id FROM ingestion_jobs j
+			WHERE ((j.state = ? AND j.available_at <= now()) OR (j.state = ? AND j.lease_expires_at < now()))
+			AND NOT EXISTS (SELECT 1 FROM ingestion_jobs e WHERE e.identity_id = j.identity_id
+				AND e.memory = j.memory AND e.document = j.document AND e.state IN ?
+				AND (e.created_at, e.id) < (j.created_at, j.id))
+			ORDER BY j.available_at LIMIT 1 FOR UPDATE SKIP LOCKED)
+		RETURNING *`, JobRunning, uuid.New(), config.Instance.JobLease.Seconds(), JobQueued, JobRunning,
+		[]string{JobQueued, JobRunning}).Scan(&jobs).Error
+	if err != nil || len(jobs) == 0 {
+		return nil, err
+	}
+	return &jobs[0], nil
+}
+
+// leased selects a job for as long as the lease taken when claiming it holds
+func (s *JobService) leased(ctx context.Context, job domain.IngestionJob) *gorm.DB {
+	return s.conn.WithContext(ctx).Model(&domain.IngestionJob{}).Where("id = ? AND lease_token = ? AND state = ?",
+		job.ID, job.LeaseToken, JobRunning)
+}
+
+func (s *JobService) process(ctx context.Context, job domain.IngestionJob) {
+	if job.Attempts > config.Instance.JobMaxAttempts {
+		s.settle(ctx, job, nil, fmt.Errorf("the worker stopped responding %d times", job.Attempts-1))
+		return
+	}
+	jobCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go s.renewLease(jobCtx, cancel, job)
+	chunking := job.Chunking.Data()
+	var file *SourceFile
+	if job.FileName != nil {
+		file = &SourceFile{Name: *job.FileName, MediaType: lo.FromPtr(job.MediaType), Data: job.File}
+	}
+	document, err := Services.KnowledgeBaseService.RecordDocument(jobCtx, job.IdentityID, job.Memory, job.Document,
+		job.Tags, job.Content, extraction.Format(job.Format), file, &chunking, func(embedded int, total int) {
+			err := s.leased(jobCtx, job).Updates(map[string]any{
+				"chunks_embedded": embedded,
+				"chunks_to_embed": total,
+			}).Error
+			if err != nil {
+				log.Printf("could not report the progress of ingestion job %s: %v", job.ID, err)
+			}
+		})
+	if err != nil {
+		s.settle(ctx, job, nil, err)
+		return
+	}
+	s.settle(ctx, job, &document, nil)
+}
+
+// renewLease extends the lease on a job until the context is done. When the lease turns out to be lost, the job is
+// cancelled, as another worker may have claimed it.
+func (s *JobService) renewLease(ctx context.Context, cancel context.CancelFunc, job domain.IngestionJob) {
+	ticker := time.NewTicker(config.Instance.JobLease / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		result := s.leased(ctx, job).Update("lease_expires_at",
+			gorm.Expr("now() + make_interval(secs => ?)", config.Instance.JobLease.Seconds()))
+		if result.Error == nil && result.RowsAffected == 0 {
+			log.Printf("lost the lease on ingestion job %s", job.ID)
+			cancel()
+			return
+		}
+	}
+}
+
+// settle records the outcome of a job. A failed job is queued again, after a delay, unless it would fail the same way
+// or has no attempts left.
+func (s *JobService) settle(ctx context.Context, job domain.IngestionJob, document *domain.Document, failure error) {
+	updates := map[string]any{
+		"state":            JobSucceeded,
+		"error":            nil,
+		"lease_token":      nil,
+		"lease_expires_at": nil,
+	}
+	switch {
+	case failure == nil:
+		updates["document_version"] = document.Version
+	case !permanentJobError(failure) && job.Attempts < config.Instance.JobMaxAttempts:
+		delay := jobRetryDelay << (job.Attempts - 1)
+		log.Printf("ingestion job %s failed, retrying in %s: %v", job.ID, delay, failure)
+		updates["state"] = JobQueued
+		updates["error"] = failure.Error()
+		updates["available_at"] = gorm.Expr("now() + make_interval(secs => ?)", delay.Seconds())
+	default:
+		log.Printf("ingestion job %s failed: %v", job.ID, failure)
+		updates["state"] = JobFailed
+		updates["error"] = failure.Error()
+	}
+	if updates["state"] != JobQueued {
+		// The content is only needed to run the job
+		updates["content"] = ""
+		updates["file"] = nil
+		updates["finished_at"] = gorm.Expr("now()")
+	}
+	if err := s.leased(ctx, job).Updates(updates).Error; err != nil {
+		log.Printf("could not record the outcome of ingestion job %s: %v", job.ID, err)
+	}
+}
+
+// permanentJobError tells whether a job that failed would fail the same way if tried again
+func permanentJobError(err error) bool {
+	return errors.Is(err, ErrInvalidChunking) || errors.Is(err, ErrModelNotFound) ||
+		errors.Is(err, ErrDimensionMismatch) || errors.Is(err, ErrInvalidEmbedding)
+}
+
+// prune deletes the jobs finished before the retention period, until the context is done
+func (s *JobService) prune(ctx context.Context) {
+	ticker := time.NewTicker(jobPruneInterval)
+	defer ticker.Stop()
+	for {
+		err := s.conn.WithContext(ctx).Where("state IN ? AND finished_at < now() - make_interval(secs => ?)",
+			[]string{JobSucceeded, JobFailed}, config.Instance.JobRetention.Seconds()).
+			Delete(&domain.IngestionJob{}).Error
+		if err != nil {
+			log.Printf("could not prune the ingestion jobs: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
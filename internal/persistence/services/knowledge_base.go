@@ -19,20 +19,24 @@ package services
 
 import (
 	"context"
-	"path/filepath"
+	"fmt"
+	"reflect"
 	"slices"
 	"strings"
 
+	"github.com/google/uuid"
 	"github.com/hashicorp/go-set/v3"
 	"github.com/lib/pq"
 	"github.com/pgvector/pgvector-go"
 	"github.com/samber/lo"
 	"github.com/theirish81/meta/internal/config"
 	"github.com/theirish81/meta/internal/dto"
+	"github.com/theirish81/meta/internal/extraction"
 	"github.com/theirish81/meta/internal/persistence/connection"
 	"github.com/theirish81/meta/internal/persistence/domain"
-	"github.com/tmc/langchaingo/textsplitter"
 	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type KnowledgeBaseService struct {
@@ -46,90 +50,509 @@ func NewKnowledgeBaseService() *KnowledgeBaseService {
 }
 
 func (s *KnowledgeBaseService) InitTables(ctx context.Context) error {
-	return s.conn.WithContext(ctx).AutoMigrate(&domain.KnowledgeChunk{})
+	if err := s.conn.WithContext(ctx).AutoMigrate(&domain.KnowledgeChunk{}, &domain.MemorySettings{},
+		&domain.Document{}, &domain.DocumentContent{}); err != nil {
+		return err
+	}
+	if err := ensureVectorDimensions(ctx, s.conn, "knowledge_chunks", Services.EmbeddingDimensions); err != nil {
+		return err
+	}
+	if err := ensureVectorIndex(ctx, s.conn, "knowledge_chunks", Services.EmbeddingDimensions); err != nil {
+		return err
+	}
+	// The full-text vector is generated by Postgres. The simple configuration neither stems nor drops stop words,
+	// so that identifiers and log lines match verbatim
+	err := s.conn.WithContext(ctx).Exec(`ALTER TABLE knowledge_chunks ADD COLUMN IF NOT EXISTS search_vector tsvector
+		GENERATED ALWAYS AS (to_tsvector('simple', chunk)) STORED`).Error
+	if err != nil {
+		return err
+	}
+	err = s.conn.WithContext(ctx).Exec("CREATE INDEX IF NOT EXISTS knowledge_chunks_search_vector_idx " +
+		"ON knowledge_chunks USING gin (search_vector)").Error
+	if err != nil {
+		return err
+	}
+	if err := backfillProvenance(ctx, s.conn, "knowledge_chunks"); err != nil {
+		return err
+	}
+	if err := backfillChunkHashes(ctx, s.conn); err != nil {
+		return err
+	}
+	return backfillDocuments(ctx, s.conn)
 }
 
-func (s *KnowledgeBaseService) Search(ctx context.Context, ownerID string, memory string, tags *[]string, q string) ([]domain.KnowledgeChunk, error) {
+const (
+	// kbSearchLimit is the default number of chunks a search returns, unless reranked
+	kbSearchLimit = 15
+	// kbHybridCandidates is the number of chunks each ranking contributes to a hybrid search
+	kbHybridCandidates = 60
+	// kbDiversityCandidates is the number of chunks diversification picks from
+	kbDiversityCandidates = 60
+	// chunkInsertBatchSize is the number of chunks inserted per statement when recording a document
+	chunkInsertBatchSize = 100
+	// progressBatchSize is the number of chunks embedded between two progress reports when recording a document
+	progressBatchSize = 64
+)
+
+func (s *KnowledgeBaseService) Search(ctx context.Context, ownerID string, memory string, q string,
+	opts SearchOptions) ([]domain.KnowledgeChunk, error) {
 	res := make([]domain.KnowledgeChunk, 0)
-	query := &domain.KnowledgeChunk{
-		IdentityID: ownerID,
-		Memory:     memory,
+	defaultLimit := kbSearchLimit
+	if Services.Reranker != nil {
+		defaultLimit = config.Instance.KbRerankTopK
+	}
+	opts, err := opts.resolve(defaultLimit, config.Instance.KbSearchMaxLimit, config.Instance.KbDistanceThreshold)
+	if err != nil {
+		return res, err
+	}
+	lambda := lo.FromPtrOr(opts.MMRLambda, config.Instance.KbMMRLambda)
+	maxPerDocument := lo.FromPtrOr(opts.MaxPerDocument, config.Instance.KbMaxPerDocument)
+	// Every stage ranks the whole window, so the results before the offset are retrieved as well
+	window := opts.Offset + opts.Limit
+	if Services.Reranker != nil {
+		window = max(window, config.Instance.KbRerankCandidates)
 	}
-	tx := s.conn.WithContext(ctx).Model(query).Where(query)
-	if tags != nil {
-		tx = tx.Where("jsonb_exists_any(tags, ?) OR document ILIKE ANY (?)", pq.Array(*tags), pq.Array(*tags))
+	if lambda < 1 || maxPerDocument > 0 {
+		window = max(window, kbDiversityCandidates)
 	}
-	embeddings, err := Services.EmbeddingService.ExtractEmbeddings([]string{q})
+	var vector *pgvector.Vector
+	if opts.Mode != SearchModeKeyword {
+		embeddings, err := Services.EmbeddingService.ExtractEmbeddings(ctx, PurposeQuery, []string{q})
+		if err != nil {
+			return res, err
+		}
+		vector = lo.ToPtr(pgvector.NewVector(embeddings[0].Vector))
+	}
+	err = s.conn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		scope := s.searchScope(ownerID, memory, opts.Tags)
+		switch opts.Mode {
+		case SearchModeKeyword:
+			return s.keywordSearch(tx.Scopes(scope), q, vector, window, &res)
+		case SearchModeHybrid:
+			candidates := max(window, kbHybridCandidates)
+			byVector := make([]domain.KnowledgeChunk, 0)
+			if err := s.vectorSearch(tx, scope, *vector, candidates, *opts.MaxDistance, &byVector); err != nil {
+				return err
+			}
+			byKeyword := make([]domain.KnowledgeChunk, 0)
+			if err := s.keywordSearch(tx.Scopes(scope), q, vector, candidates, &byKeyword); err != nil {
+				return err
+			}
+			res = lo.Slice(fuseRanks(byVector, byKeyword, config.Instance.KbHybridWeight), 0, window)
+			return nil
+		default:
+			return s.vectorSearch(tx, scope, *vector, window, *opts.MaxDistance, &res)
+		}
+	})
 	if err != nil {
 		return res, err
 	}
-	tx = tx.Select("*, embedding <=> ? as distance", pgvector.NewVector(embeddings[0].Vector))
-	tx = tx.Order("distance ASC")
-	tx = tx.Limit(15)
-	if err = tx.Find(&res).Error; err != nil {
+	res = rerank(ctx, q, res, func(chunk domain.KnowledgeChunk) string {
+		return chunk.Chunk
+	}, window)
+	res = diversify(res, lambda, maxPerDocument, opts.Offset+opts.Limit, Services.Reranker != nil)
+	res = lo.Slice(res, opts.Offset, opts.Offset+opts.Limit)
+	if opts.ContextWindow > 0 {
+		err = s.expandContext(ctx, ownerID, memory, res, opts.ContextWindow)
+	}
+	return res, err
+}
+
+// expandContext merges each chunk with up to window chunks before and after it in its document. Chunks recorded
+// before their position was tracked are left as they are.
+func (s *KnowledgeBaseService) expandContext(ctx context.Context, ownerID string, memory string,
+	chunks []domain.KnowledgeChunk, window int) error {
+	positioned := lo.Filter(chunks, func(item domain.KnowledgeChunk, _ int) bool {
+		return item.Ordinal != nil
+	})
+	if len(positioned) == 0 {
+		return nil
+	}
+	ranges := s.conn.Where("false")
+	for _, item := range positioned {
+		ranges = ranges.Or("document = ? AND ordinal BETWEEN ? AND ?", item.Document, *item.Ordinal-window,
+			*item.Ordinal+window)
+	}
+	neighbours := make([]domain.KnowledgeChunk, 0)
+	err := s.conn.WithContext(ctx).Select("document, ordinal, chunk").
+		Where("identity_id = ? AND memory = ?", ownerID, memory).Where(ranges).Order("document, ordinal").
+		Find(&neighbours).Error
+	if err != nil {
+		return err
+	}
+	byDocument := lo.GroupBy(neighbours, func(item domain.KnowledgeChunk) string {
+		return item.Document
+	})
+	for i, item := range chunks {
+		if item.Ordinal == nil {
+			continue
+		}
+		around := lo.Filter(byDocument[item.Document], func(neighbour domain.KnowledgeChunk, _ int) bool {
+			return *neighbour.Ordinal >= *item.Ordinal-window && *neighbour.Ordinal <= *item.Ordinal+window
+		})
+		if len(around) == 0 {
+			continue
+		}
+		chunks[i].Context = &domain.ChunkContext{
+			Chunk: mergeChunkTexts(lo.Map(around, func(neighbour domain.KnowledgeChunk, _ int) string {
+				return neighbour.Chunk
+			})),
+			StartOrdinal: *around[0].Ordinal,
+			EndOrdinal:   *around[len(around)-1].Ordinal,
+		}
+	}
+	return nil
+}
+
+// GetChunks returns the chunks of the owner with the given IDs, in the same order. The IDs that match no chunk are
+// skipped.
+func (s *KnowledgeBaseService) GetChunks(ctx context.Context, ownerID string, ids []uuid.UUID) (
+	[]domain.KnowledgeChunk, error) {
+	res := make([]domain.KnowledgeChunk, 0, len(ids))
+	ids = lo.Uniq(ids)
+	if len(ids) > config.Instance.KbSearchMaxLimit {
+		return res, fmt.Errorf("%w: at most %d chunks can be fetched at once", ErrTooManyChunks,
+			config.Instance.KbSearchMaxLimit)
+	}
+	if len(ids) == 0 {
+		return res, nil
+	}
+	found := make([]domain.KnowledgeChunk, 0, len(ids))
+	err := s.conn.WithContext(ctx).Where("identity_id = ? AND id IN ?", ownerID, ids).Find(&found).Error
+	if err != nil {
 		return res, err
 	}
-	res = lo.Filter(res, func(item domain.KnowledgeChunk, index int) bool {
-		return item.Distance < config.Instance.KbDistanceThreshold
+	byID := lo.KeyBy(found, func(item domain.KnowledgeChunk) uuid.UUID {
+		return item.ID
 	})
+	for _, id := range ids {
+		if item, ok := byID[id]; ok {
+			res = append(res, item)
+		}
+	}
 	return res, nil
 }
 
-func (s *KnowledgeBaseService) RecordDocument(ctx context.Context, ownerID string, memory string, document string,
-	tags []string, content string) error {
-	// We want to make sure that the document is not already present. Re-uploading a document with the same name will
-	// be considered an update of the chunks.
-	if err := s.DeleteDocument(ctx, ownerID, memory, document); err != nil {
-		return err
+// GetChunk returns a chunk of the owner by ID
+func (s *KnowledgeBaseService) GetChunk(ctx context.Context, ownerID string, id uuid.UUID) (domain.KnowledgeChunk,
+	error) {
+	chunks, err := s.GetChunks(ctx, ownerID, []uuid.UUID{id})
+	if err != nil {
+		return domain.KnowledgeChunk{}, err
 	}
-	var splitter textsplitter.TextSplitter
-	if filepath.Ext(document) == ".md" {
-		splitter = textsplitter.NewMarkdownTextSplitter(
-			textsplitter.WithChunkSize(500),
-			textsplitter.WithHeadingHierarchy(true),
-			textsplitter.WithChunkOverlap(50))
-	} else {
-		splitter = textsplitter.NewRecursiveCharacter(
-			textsplitter.WithChunkSize(500),
-			textsplitter.WithChunkOverlap(50))
+	if len(chunks) == 0 {
+		return domain.KnowledgeChunk{}, fmt.Errorf("%w: %s", ErrChunkNotFound, id)
 	}
+	return chunks[0], nil
+}
 
-	chunks, err := splitter.SplitText(content)
-	if err != nil {
+// searchScope restricts a search to the chunks of a memory, matching the tags when present
+func (s *KnowledgeBaseService) searchScope(ownerID string, memory string, tags *[]string) func(*gorm.DB) *gorm.DB {
+	return func(tx *gorm.DB) *gorm.DB {
+		query := &domain.KnowledgeChunk{
+			IdentityID: ownerID,
+			Memory:     memory,
+		}
+		tx = tx.Model(query).Where(query)
+		if tags != nil {
+			tx = tx.Where("jsonb_exists_any(tags, ?) OR document ILIKE ANY (?)", pq.Array(*tags), pq.Array(*tags))
+		}
+		return tx
+	}
+}
+
+// vectorSearch finds the chunks closest to the vector, within the distance threshold. It must run in a transaction.
+func (s *KnowledgeBaseService) vectorSearch(tx *gorm.DB, scope func(*gorm.DB) *gorm.DB, vector pgvector.Vector,
+	limit int, maxDistance float64, res *[]domain.KnowledgeChunk) error {
+	if err := vectorSearchSettings(tx, limit); err != nil {
 		return err
 	}
-	embeddings, err := Services.EmbeddingService.ExtractEmbeddings(chunks)
+	err := tx.Scopes(scope, sameEmbeddingModel).Select("*, "+distanceExpression()+" as distance", vector).
+		Order("distance ASC").Limit(limit).Find(res).Error
 	if err != nil {
 		return err
 	}
-	for _, embedding := range embeddings {
-		kc := domain.KnowledgeChunk{
+	*res = lo.Filter(*res, func(item domain.KnowledgeChunk, index int) bool {
+		return withinDistance(item.Distance, maxDistance)
+	})
+	sortByDistance(*res, func(item domain.KnowledgeChunk) *float64 {
+		return item.Distance
+	})
+	return nil
+}
+
+// keywordSearch finds the chunks containing every word of the query, by full-text rank. Quoted phrases, OR and
+// -exclusions are supported. When a vector is given, only the chunks embedded by the configured model are returned,
+// with their distance. The distance threshold is left to the vector ranking: a chunk matching the words of the query
+// is relevant however far its embedding lies.
+func (s *KnowledgeBaseService) keywordSearch(tx *gorm.DB, q string, vector *pgvector.Vector, limit int,
+	res *[]domain.KnowledgeChunk) error {
+	tx = tx.Where("search_vector @@ websearch_to_tsquery('simple', ?)", q)
+	if vector != nil {
+		tx = tx.Scopes(sameEmbeddingModel).Select("*, "+distanceExpression()+" as distance", *vector)
+	}
+	return tx.Order(clause.Expr{SQL: "ts_rank_cd(search_vector, websearch_to_tsquery('simple', ?)) DESC",
+		Vars: []any{q}}).Limit(limit).Find(res).Error
+}
+
+// RecordDocument splits a document into chunks and embeds them. The chunking settings of the memory apply, unless
+// overridden for this document. Recording a document again with the same content and settings does not re-embed it:
+// the tags are updated when they differ, and nothing happens otherwise. The content is kept, along with the file the
+// document was uploaded as, if any. When given, progress is called with the number of chunks embedded so far, out of
+// those that need embedding.
+func (s *KnowledgeBaseService) RecordDocument(ctx context.Context, ownerID string, memory string, document string,
+	tags []string, content string, format extraction.Format, file *SourceFile, chunking *domain.ChunkingSettings,
+	progress func(embedded int, total int)) (domain.Document, error) {
+	memorySettings, err := s.ChunkingSettings(ctx, ownerID, memory)
+	if err != nil {
+		return domain.Document{}, err
+	}
+	settings, err := resolveChunking(format, &memorySettings, chunking)
+	if err != nil {
+		return domain.Document{}, err
+	}
+	hash := contentHash(content)
+	existing, err := findDocument(s.conn.WithContext(ctx), ownerID, memory, document)
+	if err != nil {
+		return domain.Document{}, err
+	}
+	unchanged := existing != nil && existing.ContentHash == hash &&
+		reflect.DeepEqual(existing.Chunking.Data(), settings)
+	if unchanged {
+		// The chunks recorded before their position was tracked get it, without being embedded again
+		unpositioned, err := hasUnpositionedChunks(s.conn.WithContext(ctx), ownerID, memory, document)
+		if err != nil {
+			return domain.Document{}, err
+		}
+		unchanged = !unpositioned
+	}
+	if unchanged {
+		if sameTags(existing.Tags, tags) {
+			return *existing, s.keepContent(ctx, *existing, content, file)
+		}
+		return s.retagDocument(ctx, *existing, tags, content, file)
+	}
+	chunks, err := chunkText(ctx, document, content, format, settings)
+	if err != nil {
+		return domain.Document{}, err
+	}
+	// Re-uploading a document with the same name replaces its chunks. The chunks whose content did not change keep
+	// their rows and their embeddings, so that only new content gets embedded.
+	stored := make([]storedChunk, 0)
+	err = s.conn.WithContext(ctx).Model(&domain.KnowledgeChunk{}).Scopes(sameEmbeddingModel).
+		Select("id, content_hash, symbol, start_line, end_line, ordinal, start_offset, end_offset, headings").
+		Where("identity_id = ? AND memory = ? AND document = ?", ownerID, memory, document).Find(&stored).Error
+	if err != nil {
+		return domain.Document{}, err
+	}
+	kept := matchChunks(stored, chunks)
+	added := make([]chunk, 0)
+	for i, item := range chunks {
+		if kept[i] == nil {
+			added = append(added, item)
+		}
+	}
+	embeddings := make([]Embedding, 0, len(added))
+	for batch := range slices.Chunk(added, progressBatchSize) {
+		if progress != nil {
+			progress(len(embeddings), len(added))
+		}
+		vectors, err := Services.EmbeddingService.ExtractEmbeddings(ctx, PurposeDocument,
+			lo.Map(batch, func(item chunk, _ int) string {
+				return item.text
+			}))
+		if err != nil {
+			return domain.Document{}, err
+		}
+		embeddings = append(embeddings, vectors...)
+	}
+	if progress != nil {
+		progress(len(embeddings), len(added))
+	}
+	// The previous version stays searchable until the new one is committed: a failure leaves it as it was
+	var record domain.Document
+	err = s.conn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockDocument(tx, ownerID, memory, document); err != nil {
+			return err
+		}
+		if err := replaceChunks(tx, ownerID, memory, document, tags, settings, chunks, kept); err != nil {
+			return err
+		}
+		rows := make([]domain.KnowledgeChunk, len(embeddings))
+		for i, embedding := range embeddings {
+			rows[i] = newKnowledgeChunk(ownerID, memory, document, tags, settings, added[i])
+			rows[i].Embedding = pgvector.NewVector(embedding.Vector)
+		}
+		if len(rows) > 0 {
+			if err := tx.CreateInBatches(&rows, chunkInsertBatchSize).Error; err != nil {
+				return err
+			}
+		}
+		current, err := findDocument(tx, ownerID, memory, document)
+		if err != nil {
+			return err
+		}
+		record = domain.Document{
 			Memory:     memory,
-			Document:   document,
-			Tags:       tags,
-			Chunk:      embedding.Text,
-			Embedding:  pgvector.NewVector(embedding.Vector),
+			Name:       document,
 			IdentityID: ownerID,
+			Version:    1,
 		}
-		if err := s.conn.WithContext(ctx).Create(&kc).Error; err != nil {
+		if current != nil {
+			record = *current
+			record.Version++
+		}
+		record.Tags = tags
+		record.ContentHash = hash
+		record.Size = int64(len(content))
+		record.ChunkCount = len(chunks)
+		record.Chunking = datatypes.NewJSONType(settings)
+		if err := tx.Save(&record).Error; err != nil {
+			return err
+		}
+		return storeContent(tx, record.ID, content, file)
+	})
+	if err != nil {
+		return domain.Document{}, err
+	}
+	return record, nil
+}
+
+// lockDocument serializes the writes to a document until the end of the transaction
+func lockDocument(tx *gorm.DB, ownerID string, memory string, document string) error {
+	return tx.Exec("SELECT pg_advisory_xact_lock(hashtextextended(? || '/' || ? || '/' || ?, 0))", ownerID, memory,
+		document).Error
+}
+
+// replaceChunks deletes the stored chunks of a document that are not kept, and brings the kept ones up to date with
+// the new tags, settings and positions. It fails with ErrDocumentChanged when a kept chunk is gone in the meantime.
+func replaceChunks(tx *gorm.DB, ownerID string, memory string, document string, tags []string,
+	settings domain.ChunkingSettings, chunks []chunk, kept []*storedChunk) error {
+	keptIDs := make([]uuid.UUID, 0, len(kept))
+	for _, row := range kept {
+		if row != nil {
+			keptIDs = append(keptIDs, row.ID)
+		}
+	}
+	if len(keptIDs) == 0 {
+		return deleteChunks(tx, ownerID, memory, document)
+	}
+	err := tx.Where("identity_id = ? AND memory = ? AND document = ? AND id NOT IN ?", ownerID, memory, document,
+		keptIDs).Delete(&domain.KnowledgeChunk{}).Error
+	if err != nil {
+		return err
+	}
+	result := tx.Model(&domain.KnowledgeChunk{}).Where("id IN ?", keptIDs).Updates(map[string]any{
+		"tags":     datatypes.JSONSlice[string](tags),
+		"chunking": datatypes.NewJSONType(settings),
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected != int64(len(keptIDs)) {
+		return fmt.Errorf("%w: %s was modified while being recorded", ErrDocumentChanged, document)
+	}
+	for i, row := range kept {
+		position := newKnowledgeChunk(ownerID, memory, document, tags, settings, chunks[i])
+		if row == nil || row.samePosition(position) {
+			continue
+		}
+		err = tx.Model(&domain.KnowledgeChunk{}).Where("id = ?", row.ID).Updates(map[string]any{
+			"symbol":       position.Symbol,
+			"start_line":   position.StartLine,
+			"end_line":     position.EndLine,
+			"ordinal":      position.Ordinal,
+			"start_offset": position.StartOffset,
+			"end_offset":   position.EndOffset,
+			"headings":     position.Headings,
+		}).Error
+		if err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (s *KnowledgeBaseService) DeleteDocument(ctx context.Context, ownerID string, memory string, document string) error {
-	return s.conn.WithContext(ctx).Delete(&domain.KnowledgeChunk{}, "identity_id = ? AND memory = ? AND document = ?", ownerID, memory, document).Error
+// retagDocument replaces the tags of a document and of its chunks, leaving the chunks as they are otherwise
+func (s *KnowledgeBaseService) retagDocument(ctx context.Context, document domain.Document, tags []string,
+	content string, file *SourceFile) (domain.Document, error) {
+	document.Tags = tags
+	document.Version++
+	err := s.conn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockDocument(tx, document.IdentityID, document.Memory, document.Name); err != nil {
+			return err
+		}
+		err := tx.Model(&domain.KnowledgeChunk{}).Where("identity_id = ? AND memory = ? AND document = ?",
+			document.IdentityID, document.Memory, document.Name).Update("tags", document.Tags).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Save(&document).Error; err != nil {
+			return err
+		}
+		return storeContent(tx, document.ID, content, file)
+	})
+	return document, err
 }
 
-func (s *KnowledgeBaseService) ListDocuments(ctx context.Context, ownerID string, memory string) ([]string, error) {
-	var documents []string
-	query := &domain.KnowledgeChunk{
+// ChunkingSettings returns the chunking settings of a memory. Only the fields that differ from the configured
+// defaults are set.
+func (s *KnowledgeBaseService) ChunkingSettings(ctx context.Context, ownerID string, memory string) (
+	domain.ChunkingSettings, error) {
+	settings := make([]domain.MemorySettings, 0)
+	err := s.conn.WithContext(ctx).Where(&domain.MemorySettings{IdentityID: ownerID, Memory: memory}).Limit(1).
+		Find(&settings).Error
+	if err != nil || len(settings) == 0 {
+		return domain.ChunkingSettings{}, err
+	}
+	return settings[0].Chunking.Data(), nil
+}
+
+// UpdateChunkingSettings replaces the chunking settings of a memory. The documents already recorded keep the chunks
+// they were split into, until uploaded again.
+func (s *KnowledgeBaseService) UpdateChunkingSettings(ctx context.Context, ownerID string, memory string,
+	chunking domain.ChunkingSettings) error {
+	if _, err := resolveChunking(extraction.FormatText, &chunking); err != nil {
+		return err
+	}
+	return s.conn.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "identity_id"}, {Name: "memory"}},
+		DoUpdates: clause.AssignmentColumns([]string{"chunking"}),
+	}).Create(&domain.MemorySettings{
 		IdentityID: ownerID,
 		Memory:     memory,
-	}
-	err := s.conn.WithContext(ctx).Model(&query).Distinct("document").Where(query).
-		Pluck("document", &documents).Error
+		Chunking:   datatypes.NewJSONType(chunking),
+	}).Error
+}
+
+func (s *KnowledgeBaseService) DeleteDocument(ctx context.Context, ownerID string, memory string, document string) error {
+	return s.conn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockDocument(tx, ownerID, memory, document); err != nil {
+			return err
+		}
+		if err := deleteChunks(tx, ownerID, memory, document); err != nil {
+			return err
+		}
+		err := tx.Where("document_id IN (?)", tx.Model(&domain.Document{}).Select("id").
+			Where("identity_id = ? AND memory = ? AND name = ?", ownerID, memory, document)).
+			Delete(&domain.DocumentContent{}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Delete(&domain.Document{}, "identity_id = ? AND memory = ? AND name = ?", ownerID, memory,
+			document).Error
+	})
+}
+
+func deleteChunks(tx *gorm.DB, ownerID string, memory string, document string) error {
+	return tx.Delete(&domain.KnowledgeChunk{}, "identity_id = ? AND memory = ? AND document = ?", ownerID, memory, document).Error
+}
+
+func (s *KnowledgeBaseService) ListDocuments(ctx context.Context, ownerID string, memory string) ([]domain.Document,
+	error) {
+	documents := make([]domain.Document, 0)
+	err := s.conn.WithContext(ctx).Where(&domain.Document{IdentityID: ownerID, Memory: memory}).Order("name").
+		Find(&documents).Error
 	return documents, err
 }
 
@@ -171,7 +594,9 @@ func (s *KnowledgeBaseService) Memories(ctx context.Context, ownerID string) (dt
 		if err != nil {
 			return memories, err
 		}
-		at := append(tags, docs...)
+		at := append(tags, lo.Map(docs, func(document domain.Document, _ int) string {
+			return document.Name
+		})...)
 		slices.Sort(at)
 		memories[m] = dto.Memory{
 			AvailableTags: at,
@@ -179,3 +604,7 @@ func (s *KnowledgeBaseService) Memories(ctx context.Context, ownerID string) (dt
 	}
 	return memories, nil
 }
+
+func (s *KnowledgeBaseService) EmbeddingModels(ctx context.Context, ownerID string) (dto.EmbeddingModels, error) {
+	return embeddingModels(ctx, s.conn, &domain.KnowledgeChunk{}, ownerID)
+}
@@ -19,29 +19,26 @@ package services
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
-	"math"
 	"net/http"
 
 	"github.com/theirish81/meta/internal/config"
 )
 
 type OllamaService struct {
-	baseURL string
-	client  *http.Client
+	baseURL     string
+	batchSize   int
+	concurrency int
+	client      *http.Client
 }
 
 type OllamaEmbeddingRequest struct {
-	Model  string `json:"model"`
-	Prompt string `json:"prompt"`
-	Params Params `json:"params"`
-}
-
-type Params struct {
-	OutputDim int `json:"output_dim"`
+	Model      string   `json:"model"`
+	Input      []string `json:"input"`
+	Dimensions int      `json:"dimensions,omitempty"`
 }
 
 func (r *OllamaEmbeddingRequest) Reader() *bytes.Reader {
@@ -50,66 +47,63 @@ func (r *OllamaEmbeddingRequest) Reader() *bytes.Reader {
 }
 
 type OllamaEmbeddingResponse struct {
-	Embedding []float32 `json:"embedding"`
+	Embeddings [][]float32 `json:"embeddings"`
 }
 
-func NewOllamaService(baseURL string) *OllamaService {
+func NewOllamaService(baseURL string, batchSize int, concurrency int) *OllamaService {
 	return &OllamaService{
-		baseURL: baseURL,
-		client:  &http.Client{},
+		baseURL:     baseURL,
+		batchSize:   batchSize,
+		concurrency: concurrency,
+		client:      &http.Client{},
 	}
 }
 
-func (c *OllamaService) ExtractEmbeddings(input []string) ([]Embedding, error) {
-	embeddings := make([]Embedding, 0)
-	var mainErr error
-	for _, text := range input {
-		func() {
-			request := OllamaEmbeddingRequest{
-				Model:  config.Instance.EmbeddingModel,
-				Prompt: text,
-			}
-			resp, err := c.client.Post(fmt.Sprintf("%s/api/embeddings", c.baseURL), "application/json", request.Reader())
-			if err != nil {
-				mainErr = err
-				return
-			}
-			if resp.Body != nil {
-				defer func() {
-					_ = resp.Body.Close()
-				}()
-				data, _ := io.ReadAll(resp.Body)
-				response := OllamaEmbeddingResponse{}
-				_ = json.Unmarshal(data, &response)
-				embedding := response.Embedding
-				c.Normalize(embedding)
-				embedding = c.PadQwenToGemini(embedding)
-				embeddings = append(embeddings, Embedding{Text: text, Vector: embedding})
-			} else {
-				mainErr = errors.New("no response body")
-				return
-			}
-		}()
+func (c *OllamaService) ExtractEmbeddings(ctx context.Context, purpose EmbeddingPurpose,
+	input []string) ([]Embedding, error) {
+	vectors, err := embedInBatches(ctx, applyQueryTemplate(purpose, input), c.batchSize, c.concurrency, c.embedBatch)
+	if err != nil {
+		return nil, err
 	}
-	return embeddings, mainErr
+	embeddings := make([]Embedding, len(input))
+	for i, vector := range vectors {
+		normalize(vector)
+		embeddings[i] = Embedding{Text: input[i], Vector: vector}
+	}
+	return embeddings, nil
 }
 
-func (c *OllamaService) Normalize(v []float32) {
-	var norm float32
-	for _, x := range v {
-		norm += x * x
+func (c *OllamaService) embedBatch(ctx context.Context, batch []string) ([][]float32, error) {
+	request := OllamaEmbeddingRequest{
+		Model:      config.Instance.EmbeddingModel,
+		Input:      batch,
+		Dimensions: config.Instance.EmbeddingDimensions,
 	}
-	norm = float32(math.Sqrt(float64(norm)))
-	if norm == 0 {
-		return
+	callCtx, cancel := context.WithTimeout(ctx, config.Instance.EmbeddingTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(callCtx, http.MethodPost, fmt.Sprintf("%s/api/embed", c.baseURL),
+		request.Reader())
+	if err != nil {
+		return nil, err
 	}
-	for i := range v {
-		v[i] /= norm
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, transportError(ctx, err)
 	}
-}
-
-func (c *OllamaService) PadQwenToGemini(src []float32) []float32 {
-	dst := make([]float32, 3072)
-	copy(dst, src)
-	return dst
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, transportError(ctx, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError("ollama", resp.StatusCode, data)
+	}
+	response := OllamaEmbeddingResponse{}
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidEmbedding, err)
+	}
+	return response.Embeddings, validateVectors("ollama", response.Embeddings, len(batch))
 }
@@ -0,0 +1,125 @@
+/*
+ * Copyright (C) 2026 Simone Pezzano
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/theirish81/meta/internal/config"
+	"golang.org/x/sync/errgroup"
+)
+
+// ollamaRerankPrompt asks the model to grade one document. Ollama has no rerank endpoint, so any instruction-following
+// model served by Ollama acts as a pointwise reranker, one generation per document.
+const ollamaRerankPrompt = "Grade how relevant the document is to the query, from 0 (unrelated) to 10 (fully " +
+	`answers it). Reply with the JSON object {"score": N} only, N being the grade.` + "\n\nQuery: %s\n\nDocument: %s"
+
+// ollamaRerankFormat constrains the reply to the grade
+var ollamaRerankFormat = json.RawMessage(`{"type":"object","properties":{"score":{"type":"integer","minimum":0,` +
+	`"maximum":10}},"required":["score"]}`)
+
+type OllamaReranker struct {
+	baseURL     string
+	model       string
+	concurrency int
+	client      *http.Client
+}
+
+type OllamaGenerateRequest struct {
+	Model   string          `json:"model"`
+	Prompt  string          `json:"prompt"`
+	Format  json.RawMessage `json:"format"`
+	Stream  bool            `json:"stream"`
+	Options map[string]any  `json:"options"`
+}
+
+type OllamaGenerateResponse struct {
+	Response string `json:"response"`
+}
+
+type ollamaRerankGrade struct {
+	Score float64 `json:"score"`
+}
+
+func NewOllamaReranker(baseURL string, model string, concurrency int) *OllamaReranker {
+	return &OllamaReranker{
+		baseURL:     strings.TrimSuffix(baseURL, "/"),
+		model:       model,
+		concurrency: concurrency,
+		client:      &http.Client{},
+	}
+}
+
+func (c *OllamaReranker) Rerank(ctx context.Context, query string, documents []string) ([]float64, error) {
+	scores := make([]float64, len(documents))
+	callCtx, cancel := context.WithTimeout(ctx, config.Instance.RerankTimeout)
+	defer cancel()
+	group, callCtx := errgroup.WithContext(callCtx)
+	group.SetLimit(c.concurrency)
+	for i, document := range documents {
+		group.Go(func() error {
+			score, err := c.grade(callCtx, query, document)
+			scores[i] = score / 10
+			return err
+		})
+	}
+	return scores, group.Wait()
+}
+
+func (c *OllamaReranker) grade(ctx context.Context, query string, document string) (float64, error) {
+	body, _ := json.Marshal(OllamaGenerateRequest{
+		Model:   c.model,
+		Prompt:  fmt.Sprintf(ollamaRerankPrompt, query, document),
+		Format:  ollamaRerankFormat,
+		Options: map[string]any{"temperature": 0},
+	})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/api/generate", bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("ollama reranker returned %d: %s", resp.StatusCode, string(data))
+	}
+	response := OllamaGenerateResponse{}
+	grade := ollamaRerankGrade{}
+	if err = json.Unmarshal(data, &response); err == nil {
+		err = json.Unmarshal([]byte(response.Response), &grade)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("invalid ollama reranker response: %w", err)
+	}
+	return grade.Score, nil
+}
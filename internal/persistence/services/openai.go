@@ -0,0 +1,130 @@
+/*
+ * Copyright (C) 2026 Simone Pezzano
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/theirish81/meta/internal/config"
+)
+
+// OpenAIService talks to any OpenAI-compatible embeddings endpoint (OpenAI, vLLM, LM Studio, LocalAI, llama.cpp).
+// The base URL is expected to include the API version prefix, i.e. http://localhost:8000/v1
+type OpenAIService struct {
+	baseURL   string
+	apiKey    string
+	batchSize int
+	client    *http.Client
+}
+
+type OpenAIEmbeddingRequest struct {
+	Model          string   `json:"model"`
+	Input          []string `json:"input"`
+	EncodingFormat string   `json:"encoding_format"`
+	Dimensions     int      `json:"dimensions,omitempty"`
+}
+
+func (r *OpenAIEmbeddingRequest) Reader() *bytes.Reader {
+	data, _ := json.Marshal(r)
+	return bytes.NewReader(data)
+}
+
+type OpenAIEmbeddingResponse struct {
+	Data []OpenAIEmbeddingData `json:"data"`
+}
+
+type OpenAIEmbeddingData struct {
+	Index     int       `json:"index"`
+	Embedding []float32 `json:"embedding"`
+}
+
+func NewOpenAIService(baseURL string, apiKey string, batchSize int) *OpenAIService {
+	return &OpenAIService{
+		baseURL:   strings.TrimSuffix(baseURL, "/"),
+		apiKey:    apiKey,
+		batchSize: batchSize,
+		client:    &http.Client{},
+	}
+}
+
+func (c *OpenAIService) ExtractEmbeddings(ctx context.Context, purpose EmbeddingPurpose,
+	input []string) ([]Embedding, error) {
+	vectors, err := embedInBatches(ctx, applyQueryTemplate(purpose, input), c.batchSize, 1, c.embedBatch)
+	if err != nil {
+		return nil, err
+	}
+	embeddings := make([]Embedding, len(input))
+	for i, vector := range vectors {
+		normalize(vector)
+		embeddings[i] = Embedding{Text: input[i], Vector: vector}
+	}
+	return embeddings, nil
+}
+
+func (c *OpenAIService) embedBatch(ctx context.Context, batch []string) ([][]float32, error) {
+	request := OpenAIEmbeddingRequest{
+		Model:          config.Instance.EmbeddingModel,
+		Input:          batch,
+		EncodingFormat: "float",
+		Dimensions:     config.Instance.EmbeddingDimensions,
+	}
+	callCtx, cancel := context.WithTimeout(ctx, config.Instance.EmbeddingTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(callCtx, http.MethodPost, c.baseURL+"/embeddings", request.Reader())
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, transportError(ctx, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, transportError(ctx, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError("openai", resp.StatusCode, data)
+	}
+	response := OpenAIEmbeddingResponse{}
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidEmbedding, err)
+	}
+	// The specification does not guarantee the order of the data items, the index does
+	sort.Slice(response.Data, func(i, j int) bool {
+		return response.Data[i].Index < response.Data[j].Index
+	})
+	vectors := make([][]float32, len(response.Data))
+	for i, item := range response.Data {
+		vectors[i] = item.Embedding
+	}
+	return vectors, validateVectors("openai", vectors, len(batch))
+}
@@ -0,0 +1,135 @@
+/*
+ * Copyright (C) 2026 Simone Pezzano
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/theirish81/meta/internal/config"
+)
+
+// setConfig changes the configuration for the duration of a test
+func setConfig(t *testing.T, change func(c *config.Config)) {
+	previous := config.Instance
+	t.Cleanup(func() {
+		config.Instance = previous
+	})
+	change(&config.Instance)
+}
+
+func TestOpenAIService(t *testing.T) {
+	setConfig(t, func(c *config.Config) {
+		c.EmbeddingModel = "embedder"
+		c.EmbeddingTimeout = time.Second
+		c.EmbeddingQueryTemplate = "query: {query}"
+	})
+	// vectorOf points every text in a direction of its own, given by its last letter
+	vectorOf := func(text string) []float32 {
+		return []float32{1, float32(text[len(text)-1] - 'a')}
+	}
+	// reversed answers with the vectors of the inputs, listing the data items in reverse order
+	reversed := func(w http.ResponseWriter, request OpenAIEmbeddingRequest) {
+		response := OpenAIEmbeddingResponse{}
+		for i := len(request.Input) - 1; i >= 0; i-- {
+			response.Data = append(response.Data, OpenAIEmbeddingData{Index: i, Embedding: vectorOf(request.Input[i])})
+		}
+		_ = json.NewEncoder(w).Encode(response)
+	}
+	status := func(code int) func(http.ResponseWriter, OpenAIEmbeddingRequest) {
+		return func(w http.ResponseWriter, _ OpenAIEmbeddingRequest) {
+			w.WriteHeader(code)
+		}
+	}
+	tests := []struct {
+		name       string
+		purpose    EmbeddingPurpose
+		input      []string
+		respond    func(w http.ResponseWriter, request OpenAIEmbeddingRequest)
+		wantInputs [][]string
+		wantErr    error
+	}{
+		{name: "documents in batches", purpose: PurposeDocument, input: []string{"a", "b", "c"}, respond: reversed,
+			wantInputs: [][]string{{"a", "b"}, {"c"}}},
+		{name: "queries wrapped in the template", purpose: PurposeQuery, input: []string{"a"}, respond: reversed,
+			wantInputs: [][]string{{"query: a"}}},
+		{name: "model not found", purpose: PurposeDocument, input: []string{"a"}, respond: status(http.StatusNotFound),
+			wantErr: ErrModelNotFound},
+		{name: "rate limited", purpose: PurposeDocument, input: []string{"a"},
+			respond: status(http.StatusTooManyRequests), wantErr: ErrProviderUnavailable},
+		{name: "server error", purpose: PurposeDocument, input: []string{"a"},
+			respond: status(http.StatusInternalServerError), wantErr: ErrProviderUnavailable},
+		{name: "bad request", purpose: PurposeDocument, input: []string{"a"}, respond: status(http.StatusBadRequest),
+			wantErr: ErrInvalidEmbedding},
+		{name: "malformed response", purpose: PurposeDocument, input: []string{"a"},
+			respond: func(w http.ResponseWriter, _ OpenAIEmbeddingRequest) {
+				_, _ = w.Write([]byte("{"))
+			}, wantErr: ErrInvalidEmbedding},
+		{name: "missing vectors", purpose: PurposeDocument, input: []string{"a"},
+			respond: func(w http.ResponseWriter, _ OpenAIEmbeddingRequest) {
+				_, _ = w.Write([]byte(`{"data":[]}`))
+			}, wantErr: ErrInvalidEmbedding},
+		{name: "inconsistent dimensions", purpose: PurposeDocument, input: []string{"a", "b"},
+			respond: func(w http.ResponseWriter, _ OpenAIEmbeddingRequest) {
+				_, _ = w.Write([]byte(`{"data":[{"index":0,"embedding":[1,0]},{"index":1,"embedding":[1]}]}`))
+			}, wantErr: ErrDimensionMismatch},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var mu sync.Mutex
+			inputs := make([][]string, 0)
+			server := newStandIn(t, func(r *http.Request, request OpenAIEmbeddingRequest) bool {
+				return r.URL.Path == "/v1/embeddings" && r.Header.Get("Authorization") == "Bearer secret" &&
+					request.Model == "embedder" && request.EncodingFormat == "float"
+			}, func(w http.ResponseWriter, request OpenAIEmbeddingRequest) {
+				mu.Lock()
+				inputs = append(inputs, request.Input)
+				mu.Unlock()
+				test.respond(w, request)
+			})
+			embeddings, err := NewOpenAIService(server.URL+"/v1/", "secret", 2).ExtractEmbeddings(
+				context.Background(), test.purpose, test.input)
+			server.requireExpected(t)
+			if test.wantErr != nil {
+				if !errors.Is(err, test.wantErr) {
+					t.Fatalf("got error %v, want %v", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(inputs, test.wantInputs) {
+				t.Errorf("sent %q, want %q", inputs, test.wantInputs)
+			}
+			for i, embedding := range embeddings {
+				want := vectorOf(test.input[i])
+				normalize(want)
+				if embedding.Text != test.input[i] || !reflect.DeepEqual(embedding.Vector, want) {
+					t.Errorf("embedding %d is %+v, want the normalized %v", i, embedding, want)
+				}
+			}
+		})
+	}
+}
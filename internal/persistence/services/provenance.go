@@ -0,0 +1,74 @@
+/*
+ * Copyright (C) 2026 Simone Pezzano
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package services
+
+import (
+	"context"
+	"log"
+
+	"github.com/theirish81/meta/internal/config"
+	"github.com/theirish81/meta/internal/dto"
+	"github.com/theirish81/meta/internal/persistence/connection"
+	"gorm.io/gorm"
+)
+
+// sameEmbeddingModel restricts a query to the rows embedded by the configured provider and model, as distances
+// between vectors of different models are meaningless
+func sameEmbeddingModel(tx *gorm.DB) *gorm.DB {
+	return tx.Where("embedding_provider = ? AND embedding_model = ?", config.Instance.EmbeddingService,
+		config.Instance.EmbeddingModel)
+}
+
+// backfillProvenance attributes the rows that were embedded before provenance was recorded to the configured model.
+// This is a reasonable assumption, as the vectors have the dimension the configured model produces.
+func backfillProvenance(ctx context.Context, conn *connection.Connection, table string) error {
+	res := conn.WithContext(ctx).Table(table).Where("embedding_model = ''").Updates(map[string]any{
+		"embedding_provider": config.Instance.EmbeddingService,
+		"embedding_model":    config.Instance.EmbeddingModel,
+	})
+	if res.RowsAffected > 0 {
+		log.Printf("attributed %d rows of %s to %s/%s", res.RowsAffected, table, config.Instance.EmbeddingService,
+			config.Instance.EmbeddingModel)
+	}
+	return res.Error
+}
+
+// embeddingModels counts, per memory, the rows of a model that belong to the owner, grouped by embedding model
+func embeddingModels(ctx context.Context, conn *connection.Connection, model any,
+	ownerID string) (dto.EmbeddingModels, error) {
+	rows := make([]struct {
+		Memory            string
+		EmbeddingProvider string
+		EmbeddingModel    string
+		Count             int64
+	}, 0)
+	err := conn.WithContext(ctx).Model(model).Where("identity_id = ?", ownerID).
+		Select("memory, embedding_provider, embedding_model, count(*) AS count").
+		Group("memory, embedding_provider, embedding_model").Order("memory, count DESC").Scan(&rows).Error
+	res := make(dto.EmbeddingModels)
+	for _, row := range rows {
+		res[row.Memory] = append(res[row.Memory], dto.EmbeddingModelUsage{
+			Provider: row.EmbeddingProvider,
+			Model:    row.EmbeddingModel,
+			Count:    row.Count,
+			Current: row.EmbeddingProvider == config.Instance.EmbeddingService &&
+				row.EmbeddingModel == config.Instance.EmbeddingModel,
+		})
+	}
+	return res, err
+}
@@ -0,0 +1,45 @@
+/*
+ * Copyright (C) 2026 Simone Pezzano
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package services
+
+import (
+	"strings"
+
+	"github.com/samber/lo"
+	"github.com/theirish81/meta/internal/config"
+)
+
+// EmbeddingPurpose tells the provider what the texts are embedded for. Asymmetric models embed search queries
+// differently from the documents they are meant to retrieve.
+type EmbeddingPurpose string
+
+const (
+	PurposeDocument EmbeddingPurpose = "document"
+	PurposeQuery    EmbeddingPurpose = "query"
+)
+
+// applyQueryTemplate wraps search queries in the configured instruction template, for the providers that have no
+// notion of purpose. Documents are embedded as they are.
+func applyQueryTemplate(purpose EmbeddingPurpose, input []string) []string {
+	if purpose != PurposeQuery || config.Instance.EmbeddingQueryTemplate == "" {
+		return input
+	}
+	return lo.Map(input, func(item string, _ int) string {
+		return strings.ReplaceAll(config.Instance.EmbeddingQueryTemplate, "{query}", item)
+	})
+}
@@ -25,11 +25,17 @@ import (
 	"github.com/hashicorp/go-set/v3"
 	"github.com/lib/pq"
 	"github.com/pgvector/pgvector-go"
+	"github.com/samber/lo"
+	"github.com/theirish81/meta/internal/config"
 	"github.com/theirish81/meta/internal/dto"
 	"github.com/theirish81/meta/internal/persistence/connection"
 	"github.com/theirish81/meta/internal/persistence/domain"
+	"gorm.io/gorm"
 )
 
+// recipeSearchLimit is the default number of recipes a search returns, unless reranked
+const recipeSearchLimit = 5
+
 type RecipeService struct {
 	conn *connection.Connection
 }
@@ -41,46 +47,96 @@ func NewRecipeService() *RecipeService {
 }
 
 func (s *RecipeService) InitTables(ctx context.Context) error {
-	err := s.conn.AutoMigrate(&domain.Recipe{})
+	err := s.conn.WithContext(ctx).AutoMigrate(&domain.Recipe{})
 	if err != nil {
 		return err
 	}
-	return nil
+	if err := ensureVectorDimensions(ctx, s.conn, "recipes", Services.EmbeddingDimensions); err != nil {
+		return err
+	}
+	if err := ensureVectorIndex(ctx, s.conn, "recipes", Services.EmbeddingDimensions); err != nil {
+		return err
+	}
+	return backfillProvenance(ctx, s.conn, "recipes")
 }
 
-func (s *RecipeService) Search(ctx context.Context, ownerID string, memory string, tags *[]string, q *string) ([]domain.Recipe, error) {
+// Search finds the recipes closest to the query. Without a query, it lists the recipes by name, paginated only when
+// a limit is given.
+func (s *RecipeService) Search(ctx context.Context, ownerID string, memory string, q *string,
+	opts SearchOptions) ([]domain.Recipe, error) {
 	res := make([]domain.Recipe, 0)
 	query := &domain.Recipe{
 		IdentityID: ownerID,
 		Memory:     memory,
 	}
-	tx := s.conn.WithContext(ctx).Model(query).Where(query)
-	if tags != nil {
-		tx = tx.Where("jsonb_exists_any(tags, ?)", pq.Array(*tags))
+	paginated := opts.Limit > 0
+	defaultLimit := recipeSearchLimit
+	if Services.Reranker != nil {
+		defaultLimit = config.Instance.RecipeRerankTopK
 	}
-
+	opts, err := opts.resolve(defaultLimit, config.Instance.RecipeSearchMaxLimit,
+		config.Instance.MetaDistanceThreshold)
+	if err != nil {
+		return res, err
+	}
+	window := opts.Offset + opts.Limit
+	if Services.Reranker != nil {
+		window = max(window, config.Instance.RecipeRerankCandidates)
+	}
+	var embedding []Embedding
 	if q != nil {
-		embedding, err := Services.EmbeddingService.ExtractEmbeddings([]string{*q})
-		if err != nil {
+		if embedding, err = Services.EmbeddingService.ExtractEmbeddings(ctx, PurposeQuery, []string{*q}); err != nil {
 			return res, err
 		}
-		tx = tx.Select("*, embedding <=> ? as distance", pgvector.NewVector(embedding[0].Vector))
+	}
+	err = s.conn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		tx = tx.Model(query).Where(query)
+		if opts.Tags != nil {
+			tx = tx.Where("jsonb_exists_any(tags, ?)", pq.Array(*opts.Tags))
+		}
+		if q == nil {
+			tx = tx.Order("name").Offset(opts.Offset)
+			if paginated {
+				tx = tx.Limit(opts.Limit)
+			}
+			return tx.Find(&res).Error
+		}
+		if err := vectorSearchSettings(tx, window); err != nil {
+			return err
+		}
+		tx = tx.Scopes(sameEmbeddingModel)
+		tx = tx.Select("*, "+distanceExpression()+" as distance", pgvector.NewVector(embedding[0].Vector))
 		tx = tx.Order("distance ASC")
-		tx = tx.Limit(5)
+		tx = tx.Limit(window)
+		return tx.Find(&res).Error
+	})
+	if err != nil || q == nil {
+		return res, err
 	}
-	err := tx.Find(&res).Error
-	return res, err
+	res = lo.Filter(res, func(item domain.Recipe, _ int) bool {
+		return withinDistance(item.Distance, *opts.MaxDistance)
+	})
+	sortByDistance(res, func(recipe domain.Recipe) *float64 {
+		return recipe.Distance
+	})
+	res = rerank(ctx, *q, res, func(recipe domain.Recipe) string {
+		return recipe.Name + ": " + recipe.Description
+	}, window)
+	return lo.Slice(res, opts.Offset, opts.Offset+opts.Limit), nil
 }
 
 func (s *RecipeService) Create(ctx context.Context, ownerID string, memory string, meta domain.Recipe) (domain.Recipe, error) {
 	meta.ID = uuid.New()
 	meta.IdentityID = ownerID
 	meta.Memory = memory
-	embeddings, err := Services.EmbeddingService.ExtractEmbeddings([]string{meta.Name + ": " + meta.Description})
+	embeddings, err := Services.EmbeddingService.ExtractEmbeddings(ctx, PurposeDocument,
+		[]string{meta.Name + ": " + meta.Description})
 	if err != nil {
 		return meta, err
 	}
 	meta.Embedding = pgvector.NewVector(embeddings[0].Vector)
+	meta.EmbeddingProvider = config.Instance.EmbeddingService
+	meta.EmbeddingModel = config.Instance.EmbeddingModel
 	err = s.conn.WithContext(ctx).Create(&meta).Error
 	return meta, err
 }
@@ -143,3 +199,7 @@ func (s *RecipeService) Update(ctx context.Context, ownerID string, memory strin
 	}
 	return s.Show(ctx, ownerID, memory, recipeID)
 }
+
+func (s *RecipeService) EmbeddingModels(ctx context.Context, ownerID string) (dto.EmbeddingModels, error) {
+	return embeddingModels(ctx, s.conn, &domain.Recipe{}, ownerID)
+}
@@ -0,0 +1,240 @@
+/*
+ * Copyright (C) 2026 Simone Pezzano
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/pgvector/pgvector-go"
+	"github.com/samber/lo"
+	"github.com/theirish81/meta/internal/config"
+	"github.com/theirish81/meta/internal/persistence/connection"
+	"github.com/theirish81/meta/internal/persistence/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type reindexTarget struct {
+	table string
+	// text is the SQL expression producing the text that gets embedded
+	text string
+}
+
+var reindexTargets = []reindexTarget{
+	{table: "knowledge_chunks", text: "chunk"},
+	{table: "recipes", text: "name || ': ' || description"},
+}
+
+type reindexRow struct {
+	ID   uuid.UUID
+	Text string
+}
+
+type ReindexOptions struct {
+	Subject   string
+	Memory    string
+	BatchSize int
+	// Force reindexes the rows that were already embedded by the configured model, too
+	Force bool
+}
+
+// filter returns the SQL condition and arguments restricting a table to the selected subject and memory
+func (o ReindexOptions) filter() (string, []any) {
+	condition := "true"
+	args := make([]any, 0)
+	if o.Subject != "" {
+		condition += " AND identity_id = ?"
+		args = append(args, o.Subject)
+	}
+	if o.Memory != "" {
+		condition += " AND memory = ?"
+		args = append(args, o.Memory)
+	}
+	return condition, args
+}
+
+type ReindexPlan struct {
+	Table string
+	// Rows is the number of rows that need a new vector
+	Rows             int64
+	Staged           int64
+	StoredDimensions int
+}
+
+func (p ReindexPlan) DimensionChange() bool {
+	return p.StoredDimensions != Services.EmbeddingDimensions
+}
+
+// ReindexService recomputes the embeddings of knowledge chunks and recipes with the configured model. New vectors
+// are staged in a separate table, so an interrupted run resumes where it stopped, and are swapped in with a single
+// transaction per table once all of them are available.
+type ReindexService struct {
+	conn *connection.Connection
+}
+
+func NewReindexService() *ReindexService {
+	return &ReindexService{
+		conn: connection.Conn,
+	}
+}
+
+func (s *ReindexService) InitTables(ctx context.Context) error {
+	return s.conn.WithContext(ctx).AutoMigrate(&domain.ReindexVector{})
+}
+
+func (s *ReindexService) Plan(ctx context.Context, opts ReindexOptions) ([]ReindexPlan, error) {
+	plans := make([]ReindexPlan, 0)
+	for _, target := range reindexTargets {
+		plan := ReindexPlan{Table: target.table}
+		var err error
+		if plan.StoredDimensions, err = storedDimensions(s.conn.WithContext(ctx), target.table); err != nil {
+			return plans, err
+		}
+		if err = s.stale(ctx, target, opts).Count(&plan.Rows).Error; err != nil {
+			return plans, err
+		}
+		var pending int64
+		if err = s.pending(ctx, target, opts).Count(&pending).Error; err != nil {
+			return plans, err
+		}
+		plan.Staged = plan.Rows - pending
+		if plan.DimensionChange() && plan.Rows > 0 && (opts.Subject != "" || opts.Memory != "") {
+			return plans, fmt.Errorf("%s holds %d-dimensional embeddings and %s produces %d: changing the dimension "+
+				"requires reindexing every row, without filters", target.table, plan.StoredDimensions,
+				config.Instance.EmbeddingModel, Services.EmbeddingDimensions)
+		}
+		plans = append(plans, plan)
+	}
+	return plans, nil
+}
+
+// stale selects the rows of a target that need a new vector
+func (s *ReindexService) stale(ctx context.Context, target reindexTarget, opts ReindexOptions) *gorm.DB {
+	condition, args := opts.filter()
+	tx := s.conn.WithContext(ctx).Table(target.table).Where(condition, args...)
+	if !opts.Force {
+		tx = tx.Where("(embedding_provider <> ? OR embedding_model <> ? OR vector_dims(embedding) <> ?)",
+			config.Instance.EmbeddingService, config.Instance.EmbeddingModel, Services.EmbeddingDimensions)
+	}
+	return tx
+}
+
+// pending selects the stale rows of a target that have no staged vector for the configured model yet
+func (s *ReindexService) pending(ctx context.Context, target reindexTarget, opts ReindexOptions) *gorm.DB {
+	return s.stale(ctx, target, opts).
+		Where("NOT EXISTS (SELECT 1 FROM reindex_vectors r WHERE r.source = ? AND r.row_id = "+target.table+
+			".id AND r.provider = ? AND r.model = ?)", target.table, config.Instance.EmbeddingService,
+			config.Instance.EmbeddingModel)
+}
+
+func (s *ReindexService) Run(ctx context.Context, opts ReindexOptions,
+	progress func(plan ReindexPlan, done int64)) error {
+	plans, err := s.Plan(ctx, opts)
+	if err != nil {
+		return err
+	}
+	for i, target := range reindexTargets {
+		if err := s.stage(ctx, target, opts, plans[i], progress); err != nil {
+			return err
+		}
+		if err := s.apply(ctx, target, opts, plans[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *ReindexService) stage(ctx context.Context, target reindexTarget, opts ReindexOptions, plan ReindexPlan,
+	progress func(plan ReindexPlan, done int64)) error {
+	done := plan.Staged
+	for {
+		rows := make([]reindexRow, 0)
+		err := s.pending(ctx, target, opts).Select("id, " + target.text + " AS text").Order("id").
+			Limit(opts.BatchSize).Scan(&rows).Error
+		if err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		texts := lo.Map(rows, func(item reindexRow, _ int) string {
+			return item.Text
+		})
+		embeddings, err := Services.EmbeddingService.ExtractEmbeddings(ctx, PurposeDocument, texts)
+		if err != nil {
+			return err
+		}
+		vectors := make([]domain.ReindexVector, len(rows))
+		for i, row := range rows {
+			vectors[i] = domain.ReindexVector{
+				Source:   target.table,
+				RowID:    row.ID,
+				Provider: config.Instance.EmbeddingService,
+				Model:    config.Instance.EmbeddingModel,
+				Vector:   pgvector.NewVector(embeddings[i].Vector),
+			}
+		}
+		err = s.conn.WithContext(ctx).Clauses(clause.OnConflict{UpdateAll: true}).Create(&vectors).Error
+		if err != nil {
+			return err
+		}
+		done += int64(len(rows))
+		progress(plan, done)
+	}
+}
+
+// apply swaps the staged vectors in. When the dimension changes, the column constraint is lifted for the duration
+// of the transaction, and restored once every row holds a vector of the new dimension, along with the index.
+func (s *ReindexService) apply(ctx context.Context, target reindexTarget, opts ReindexOptions,
+	plan ReindexPlan) error {
+	condition, args := opts.filter()
+	return s.conn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if plan.DimensionChange() {
+			if err := dropVectorIndex(tx, target.table, false); err != nil {
+				return err
+			}
+			if err := tx.Exec("ALTER TABLE " + target.table + " ALTER COLUMN embedding TYPE vector").Error; err != nil {
+				return err
+			}
+		}
+		err := tx.Exec("UPDATE "+target.table+" SET embedding = r.vector, embedding_provider = r.provider, "+
+			"embedding_model = r.model FROM reindex_vectors r "+
+			"WHERE r.source = ? AND r.row_id = "+target.table+".id AND r.provider = ? AND r.model = ? AND "+condition,
+			append([]any{target.table, config.Instance.EmbeddingService, config.Instance.EmbeddingModel}, args...)...).
+			Error
+		if err != nil {
+			return err
+		}
+		if plan.DimensionChange() {
+			err = tx.Exec(fmt.Sprintf("ALTER TABLE %s ALTER COLUMN embedding TYPE vector(%d)", target.table,
+				Services.EmbeddingDimensions)).Error
+			if err != nil {
+				return errors.Join(errors.New("some rows could not be reindexed, run the command again"), err)
+			}
+			index := vectorIndexName(target.table)
+			if err = createVectorIndex(tx, target.table, index, Services.EmbeddingDimensions, false); err != nil {
+				return err
+			}
+		}
+		return tx.Exec("DELETE FROM reindex_vectors WHERE source = ? AND row_id IN (SELECT id FROM "+target.table+
+			" WHERE "+condition+")", append([]any{target.table}, args...)...).Error
+	})
+}
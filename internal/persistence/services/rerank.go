@@ -0,0 +1,166 @@
+/*
+ * Copyright (C) 2026 Simone Pezzano
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package services
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/samber/lo"
+	"github.com/theirish81/meta/internal/config"
+)
+
+// RerankAPIService talks to a /rerank endpoint. The tei dialect is the one of Hugging Face Text Embeddings Inference,
+// the openai dialect is the Jina/Cohere one served by vLLM, llama.cpp, LocalAI and most hosted APIs.
+type RerankAPIService struct {
+	dialect string
+	baseURL string
+	apiKey  string
+	model   string
+	client  *http.Client
+}
+
+type TEIRerankRequest struct {
+	Query string   `json:"query"`
+	Texts []string `json:"texts"`
+}
+
+type TEIRerankResult struct {
+	Index int     `json:"index"`
+	Score float64 `json:"score"`
+}
+
+type OpenAIRerankRequest struct {
+	Model     string   `json:"model,omitempty"`
+	Query     string   `json:"query"`
+	Documents []string `json:"documents"`
+}
+
+type OpenAIRerankResponse struct {
+	Results []OpenAIRerankResult `json:"results"`
+}
+
+type OpenAIRerankResult struct {
+	Index          int     `json:"index"`
+	RelevanceScore float64 `json:"relevance_score"`
+}
+
+func NewRerankAPIService(dialect string, baseURL string, apiKey string, model string) *RerankAPIService {
+	return &RerankAPIService{
+		dialect: dialect,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		apiKey:  apiKey,
+		model:   model,
+		client:  &http.Client{},
+	}
+}
+
+func (c *RerankAPIService) Rerank(ctx context.Context, query string, documents []string) ([]float64, error) {
+	var request any = OpenAIRerankRequest{Model: c.model, Query: query, Documents: documents}
+	if c.dialect == "tei" {
+		request = TEIRerankRequest{Query: query, Texts: documents}
+	}
+	body, _ := json.Marshal(request)
+	callCtx, cancel := context.WithTimeout(ctx, config.Instance.RerankTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(callCtx, http.MethodPost, c.baseURL+"/rerank", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s reranker returned %d: %s", c.dialect, resp.StatusCode, string(data))
+	}
+	results := make([]TEIRerankResult, 0)
+	if c.dialect == "tei" {
+		err = json.Unmarshal(data, &results)
+	} else {
+		response := OpenAIRerankResponse{}
+		err = json.Unmarshal(data, &response)
+		results = lo.Map(response.Results, func(item OpenAIRerankResult, _ int) TEIRerankResult {
+			return TEIRerankResult{Index: item.Index, Score: item.RelevanceScore}
+		})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s reranker response: %w", c.dialect, err)
+	}
+	// Documents the reranker left out are considered irrelevant
+	scores := slices.Repeat([]float64{math.Inf(-1)}, len(documents))
+	for _, result := range results {
+		if result.Index < 0 || result.Index >= len(documents) {
+			return nil, fmt.Errorf("invalid %s reranker response: index %d out of range", c.dialect, result.Index)
+		}
+		scores[result.Index] = result.Score
+	}
+	return scores, nil
+}
+
+// rerank reorders the candidates by relevance to the query, keeping the topK most relevant ones scoring at least
+// RERANK_MIN_SCORE. Reranking is best effort: without a reranker, or when it fails, the candidates keep the
+// retrieval order.
+func rerank[T any](ctx context.Context, query string, candidates []T, text func(T) string, topK int) []T {
+	if Services.Reranker == nil || len(candidates) == 0 {
+		return lo.Slice(candidates, 0, topK)
+	}
+	if err := Services.RerankBreaker.Allow(); err != nil {
+		return lo.Slice(candidates, 0, topK)
+	}
+	scores, err := Services.Reranker.Rerank(ctx, query, lo.Map(candidates, func(item T, _ int) string {
+		return text(item)
+	}))
+	if err != nil {
+		if ctx.Err() == nil {
+			Services.RerankBreaker.Failure()
+			log.Printf("reranking failed, keeping the retrieval order: %s", err)
+		}
+		return lo.Slice(candidates, 0, topK)
+	}
+	Services.RerankBreaker.Success()
+	order := lo.Filter(lo.Range(len(candidates)), func(i int, _ int) bool {
+		return scores[i] >= config.Instance.RerankMinScore
+	})
+	slices.SortStableFunc(order, func(a, b int) int {
+		return cmp.Compare(scores[b], scores[a])
+	})
+	return lo.Map(lo.Slice(order, 0, topK), func(i int, _ int) T {
+		return candidates[i]
+	})
+}
@@ -0,0 +1,150 @@
+/*
+ * Copyright (C) 2026 Simone Pezzano
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/theirish81/meta/internal/config"
+)
+
+func TestRerankAPIService(t *testing.T) {
+	setConfig(t, func(c *config.Config) {
+		c.RerankTimeout = time.Second
+	})
+	tests := []struct {
+		name        string
+		dialect     string
+		model       string
+		wantRequest string
+		response    string
+		status      int
+		want        []float64
+		wantErr     bool
+	}{
+		{name: "tei", dialect: "tei", wantRequest: `{"query":"q","texts":["a","b","c"]}`,
+			response: `[{"index":2,"score":0.9},{"index":0,"score":0.5},{"index":1,"score":0.1}]`,
+			want:     []float64{0.5, 0.1, 0.9}},
+		{name: "openai", dialect: "openai", model: "reranker",
+			wantRequest: `{"model":"reranker","query":"q","documents":["a","b","c"]}`,
+			response: `{"results":[{"index":1,"relevance_score":0.8},{"index":0,"relevance_score":0.2},` +
+				`{"index":2,"relevance_score":0.4}]}`,
+			want: []float64{0.2, 0.8, 0.4}},
+		{name: "openai without model", dialect: "openai",
+			wantRequest: `{"query":"q","documents":["a","b","c"]}`,
+			response: `{"results":[{"index":0,"relevance_score":1},{"index":1,"relevance_score":1},` +
+				`{"index":2,"relevance_score":1}]}`,
+			want: []float64{1, 1, 1}},
+		{name: "documents left out", dialect: "tei", wantRequest: `{"query":"q","texts":["a","b","c"]}`,
+			response: `[{"index":1,"score":0.3}]`, want: []float64{math.Inf(-1), 0.3, math.Inf(-1)}},
+		{name: "index out of range", dialect: "tei", wantRequest: `{"query":"q","texts":["a","b","c"]}`,
+			response: `[{"index":3,"score":0.3}]`, wantErr: true},
+		{name: "malformed response", dialect: "openai", wantRequest: `{"query":"q","documents":["a","b","c"]}`,
+			response: `[]`, wantErr: true},
+		{name: "server error", dialect: "tei", wantRequest: `{"query":"q","texts":["a","b","c"]}`,
+			status: http.StatusInternalServerError, wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := newStandIn(t, func(r *http.Request, request json.RawMessage) bool {
+				return r.URL.Path == "/v1/rerank" && r.Header.Get("Authorization") == "Bearer secret" &&
+					string(request) == test.wantRequest
+			}, func(w http.ResponseWriter, _ json.RawMessage) {
+				if test.status != 0 {
+					w.WriteHeader(test.status)
+				}
+				_, _ = w.Write([]byte(test.response))
+			})
+			scores, err := NewRerankAPIService(test.dialect, server.URL+"/v1", "secret", test.model).Rerank(
+				context.Background(), "q", []string{"a", "b", "c"})
+			server.requireExpected(t)
+			if test.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(scores, test.want) {
+				t.Errorf("got %v, want %v", scores, test.want)
+			}
+		})
+	}
+}
+
+func TestOllamaReranker(t *testing.T) {
+	setConfig(t, func(c *config.Config) {
+		c.RerankTimeout = time.Second
+	})
+	// graded grades document a 7 and the others 2
+	graded := func(w http.ResponseWriter, request OllamaGenerateRequest) {
+		grade := 2
+		if strings.HasSuffix(request.Prompt, "Document: a") {
+			grade = 7
+		}
+		_ = json.NewEncoder(w).Encode(OllamaGenerateResponse{Response: fmt.Sprintf(`{"score":%d}`, grade)})
+	}
+	tests := []struct {
+		name    string
+		respond func(w http.ResponseWriter, request OllamaGenerateRequest)
+		want    []float64
+		wantErr bool
+	}{
+		{name: "grades scaled down", respond: graded, want: []float64{0.7, 0.2}},
+		{name: "reply out of format", respond: func(w http.ResponseWriter, _ OllamaGenerateRequest) {
+			_ = json.NewEncoder(w).Encode(OllamaGenerateResponse{Response: "7"})
+		}, wantErr: true},
+		{name: "server error", respond: func(w http.ResponseWriter, _ OllamaGenerateRequest) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}, wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := newStandIn(t, func(r *http.Request, request OllamaGenerateRequest) bool {
+				return r.URL.Path == "/api/generate" && request.Model == "grader" && !request.Stream &&
+					strings.Contains(request.Prompt, `{"score": N}`) && strings.Contains(request.Prompt, "Query: q") &&
+					reflect.DeepEqual(request.Format, ollamaRerankFormat)
+			}, test.respond)
+			scores, err := NewOllamaReranker(server.URL+"/", "grader", 2).Rerank(context.Background(), "q",
+				[]string{"a", "b"})
+			server.requireExpected(t)
+			if test.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(scores, test.want) {
+				t.Errorf("got %v, want %v", scores, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+/*
+ * Copyright (C) 2026 Simone Pezzano
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+type BreakerState string
+
+const (
+	BreakerClosed   BreakerState = "closed"
+	BreakerOpen     BreakerState = "open"
+	BreakerHalfOpen BreakerState = "half-open"
+)
+
+// ErrCircuitOpen is returned without calling the provider while the circuit breaker is open
+var ErrCircuitOpen = fmt.Errorf("%w: circuit breaker is open", ErrProviderUnavailable)
+
+type BreakerStatus struct {
+	State               BreakerState `json:"state"`
+	ConsecutiveFailures int          `json:"consecutive_failures"`
+	OpenedAt            *time.Time   `json:"opened_at,omitempty"`
+}
+
+// CircuitBreaker opens after threshold consecutive failures and rejects calls until cooldown has elapsed. After that,
+// a single call is let through to probe the provider: its success closes the breaker, its failure opens it for another
+// cooldown. The other calls are rejected while the probe is in flight.
+type CircuitBreaker struct {
+	mu        sync.Mutex
+	name      string
+	threshold int
+	cooldown  time.Duration
+	failures  int
+	openedAt  time.Time
+	probing   bool
+	probedAt  time.Time
+}
+
+func NewCircuitBreaker(name string, threshold int, cooldown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		name:      name,
+		threshold: threshold,
+		cooldown:  cooldown,
+	}
+}
+
+func (b *CircuitBreaker) state() BreakerState {
+	switch {
+	case b.failures < b.threshold:
+		return BreakerClosed
+	case time.Since(b.openedAt) < b.cooldown:
+		return BreakerOpen
+	default:
+		return BreakerHalfOpen
+	}
+}
+
+// Allow tells whether a call can go through, and makes it the probe when the breaker is half-open. A probe that does
+// not report back within a cooldown, as when its caller gives up, gives way to the next call.
+func (b *CircuitBreaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state() {
+	case BreakerOpen:
+		return ErrCircuitOpen
+	case BreakerHalfOpen:
+		if b.probing && time.Since(b.probedAt) < b.cooldown {
+			return ErrCircuitOpen
+		}
+		b.probing, b.probedAt = true, time.Now()
+	}
+	return nil
+}
+
+func (b *CircuitBreaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state() != BreakerClosed {
+		log.Printf("%s circuit breaker closed", b.name)
+	}
+	b.failures = 0
+	b.probing = false
+}
+
+func (b *CircuitBreaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	previous := b.state()
+	b.failures++
+	if b.failures >= b.threshold && previous != BreakerOpen {
+		b.openedAt = time.Now()
+		log.Printf("%s circuit breaker opened after %d consecutive failures", b.name, b.failures)
+	}
+	b.probing = false
+}
+
+func (b *CircuitBreaker) Status() BreakerStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	status := BreakerStatus{State: b.state(), ConsecutiveFailures: b.failures}
+	if status.State != BreakerClosed {
+		openedAt := b.openedAt
+		status.OpenedAt = &openedAt
+	}
+	return status
+}
+
+// ResilientEmbeddingService decorates an EmbeddingService, retrying transient failures with jittered exponential
+// backoff and failing fast through a circuit breaker when calls keep failing after their retries.
+type ResilientEmbeddingService struct {
+	service    EmbeddingService
+	breaker    *CircuitBreaker
+	maxRetries int
+	baseDelay  time.Duration
+	maxDelay   time.Duration
+}
+
+func NewResilientEmbeddingService(service EmbeddingService, breaker *CircuitBreaker, maxRetries int,
+	baseDelay time.Duration, maxDelay time.Duration) *ResilientEmbeddingService {
+	return &ResilientEmbeddingService{
+		service:    service,
+		breaker:    breaker,
+		maxRetries: maxRetries,
+		baseDelay:  baseDelay,
+		maxDelay:   maxDelay,
+	}
+}
+
+func (s *ResilientEmbeddingService) ExtractEmbeddings(ctx context.Context, purpose EmbeddingPurpose,
+	input []string) ([]Embedding, error) {
+	// The breaker is asked once per call, so that the retries of the call probing the provider go through
+	if err := s.breaker.Allow(); err != nil {
+		return nil, err
+	}
+	for attempt := 0; ; attempt++ {
+		embeddings, err := s.service.ExtractEmbeddings(ctx, purpose, input)
+		switch {
+		case err == nil:
+			s.breaker.Success()
+			return embeddings, nil
+		case ctx.Err() != nil:
+			return nil, err
+		case !errors.Is(err, ErrProviderUnavailable):
+			// The provider answered, it's the request that cannot be fulfilled
+			s.breaker.Success()
+			return nil, err
+		}
+		if attempt >= s.maxRetries {
+			// A call counts as a single failure however many times it was retried, so that one caller alone
+			// cannot open the breaker for everyone
+			s.breaker.Failure()
+			return nil, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(s.backoff(attempt)):
+		}
+	}
+}
+
+// backoff returns a random delay between half and the whole of the exponential delay for the given attempt
+func (s *ResilientEmbeddingService) backoff(attempt int) time.Duration {
+	delay := s.maxDelay
+	if attempt < 16 {
+		delay = min(s.baseDelay<<attempt, s.maxDelay)
+	}
+	half := delay / 2
+	return half + rand.N(half+1)
+}
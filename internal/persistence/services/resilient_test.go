@@ -0,0 +1,69 @@
+/*
+ * Copyright (C) 2026 Simone Pezzano
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	tests := []struct {
+		name  string
+		steps []string
+		want  BreakerState
+	}{
+		{name: "closed below the threshold", steps: []string{"failure", "success", "failure", "allowed"},
+			want: BreakerClosed},
+		{name: "opens at the threshold", steps: []string{"failure", "failure", "rejected"}, want: BreakerOpen},
+		{name: "a single probe after the cooldown",
+			steps: []string{"failure", "failure", "cooldown", "allowed", "rejected", "rejected"},
+			want:  BreakerHalfOpen},
+		{name: "successful probe closes",
+			steps: []string{"failure", "failure", "cooldown", "allowed", "success", "allowed", "allowed"},
+			want:  BreakerClosed},
+		{name: "failed probe opens again",
+			steps: []string{"failure", "failure", "cooldown", "allowed", "failure", "rejected"}, want: BreakerOpen},
+		{name: "silent probe gives way after a cooldown",
+			steps: []string{"failure", "failure", "cooldown", "allowed", "cooldown", "allowed", "rejected"},
+			want:  BreakerHalfOpen},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			breaker := NewCircuitBreaker("test", 2, time.Hour)
+			for i, step := range test.steps {
+				switch step {
+				case "success":
+					breaker.Success()
+				case "failure":
+					breaker.Failure()
+				case "cooldown":
+					breaker.openedAt = breaker.openedAt.Add(-breaker.cooldown)
+					breaker.probedAt = breaker.probedAt.Add(-breaker.cooldown)
+				case "allowed", "rejected":
+					if err := breaker.Allow(); (err == nil) != (step == "allowed") {
+						t.Fatalf("step %d: got %v, want the call %s", i, err, step)
+					}
+				}
+			}
+			if state := breaker.Status().State; state != test.want {
+				t.Errorf("the breaker is %s, want %s", state, test.want)
+			}
+		})
+	}
+}
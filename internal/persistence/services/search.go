@@ -0,0 +1,201 @@
+/*
+ * Copyright (C) 2026 Simone Pezzano
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package services
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/pgvector/pgvector-go"
+	"github.com/theirish81/meta/internal/config"
+	"github.com/theirish81/meta/internal/persistence/domain"
+)
+
+// SearchMode is how knowledge chunks are matched against a query
+type SearchMode string
+
+const (
+	// SearchModeVector ranks chunks by the cosine distance of their embedding to the embedding of the query
+	SearchModeVector SearchMode = "vector"
+	// SearchModeKeyword ranks the chunks containing every word of the query by full-text rank
+	SearchModeKeyword SearchMode = "keyword"
+	// SearchModeHybrid fuses the vector and keyword rankings
+	SearchModeHybrid SearchMode = "hybrid"
+)
+
+// ParseSearchMode validates a search mode, defaulting to SearchModeVector when empty
+func ParseSearchMode(mode string) (SearchMode, error) {
+	switch SearchMode(mode) {
+	case "":
+		return SearchModeVector, nil
+	case SearchModeVector, SearchModeKeyword, SearchModeHybrid:
+		return SearchMode(mode), nil
+	}
+	return "", fmt.Errorf("%w: unknown mode %q, use one of vector, keyword or hybrid", ErrInvalidSearch, mode)
+}
+
+// SearchOptions tunes a search. Zero values fall back to the configured defaults
+type SearchOptions struct {
+	Tags *[]string
+	// Mode only applies to knowledge searches
+	Mode   SearchMode
+	Limit  int
+	Offset int
+	// MaxDistance overrides the configured distance threshold
+	MaxDistance *float64
+	// MMRLambda and MaxPerDocument override the configured diversification. They only apply to knowledge searches
+	MMRLambda      *float64
+	MaxPerDocument *int
+	// ContextWindow is the number of neighbouring chunks merged with each result, on each side. It only applies to
+	// knowledge searches
+	ContextWindow int
+}
+
+// resolve validates the options and fills in the defaults. Limits above the cap are lowered to the cap.
+func (o SearchOptions) resolve(defaultLimit int, maxLimit int, defaultDistance float64) (SearchOptions, error) {
+	if o.Limit < 0 || o.Offset < 0 {
+		return o, fmt.Errorf("%w: limit and offset cannot be negative", ErrInvalidSearch)
+	}
+	if o.Offset > config.Instance.SearchMaxOffset {
+		return o, fmt.Errorf("%w: offset cannot exceed %d", ErrInvalidSearch, config.Instance.SearchMaxOffset)
+	}
+	if o.MaxDistance != nil && (*o.MaxDistance < 0 || *o.MaxDistance > 2) {
+		return o, fmt.Errorf("%w: max_distance must be between 0 and 2", ErrInvalidSearch)
+	}
+	if o.MMRLambda != nil && (*o.MMRLambda < 0 || *o.MMRLambda > 1) {
+		return o, fmt.Errorf("%w: mmr_lambda must be between 0 and 1", ErrInvalidSearch)
+	}
+	if o.MaxPerDocument != nil && *o.MaxPerDocument < 0 {
+		return o, fmt.Errorf("%w: max_per_document cannot be negative", ErrInvalidSearch)
+	}
+	if o.ContextWindow < 0 || o.ContextWindow > config.Instance.KbMaxContextWindow {
+		return o, fmt.Errorf("%w: context_window must be between 0 and %d", ErrInvalidSearch,
+			config.Instance.KbMaxContextWindow)
+	}
+	if o.Limit == 0 {
+		o.Limit = defaultLimit
+	}
+	o.Limit = min(o.Limit, maxLimit)
+	if o.MaxDistance == nil {
+		o.MaxDistance = &defaultDistance
+	}
+	return o, nil
+}
+
+// withinDistance tells whether a search result is within the distance threshold. Results without a distance, as
+// found by keyword, always are.
+func withinDistance(distance *float64, maxDistance float64) bool {
+	return distance == nil || *distance < maxDistance
+}
+
+// sortByDistance orders vector search results by distance, as relaxed iterative index scans may return them slightly
+// out of order
+func sortByDistance[T any](items []T, distance func(T) *float64) {
+	slices.SortStableFunc(items, func(a T, b T) int {
+		return cmp.Compare(*distance(a), *distance(b))
+	})
+}
+
+// rrfK dampens the advantage of the top ranks in reciprocal rank fusion
+const rrfK = 60
+
+// fuseRanks merges the vector and keyword rankings of the same query by weighted reciprocal rank fusion. The weight
+// applies to the vector ranks, and its complement to the keyword ranks.
+func fuseRanks(byVector []domain.KnowledgeChunk, byKeyword []domain.KnowledgeChunk,
+	weight float64) []domain.KnowledgeChunk {
+	scores := make(map[uuid.UUID]float64)
+	res := make([]domain.KnowledgeChunk, 0, len(byVector)+len(byKeyword))
+	for i, chunk := range byVector {
+		scores[chunk.ID] += weight / float64(rrfK+i+1)
+		res = append(res, chunk)
+	}
+	for i, chunk := range byKeyword {
+		if _, ok := scores[chunk.ID]; !ok {
+			res = append(res, chunk)
+		}
+		scores[chunk.ID] += (1 - weight) / float64(rrfK+i+1)
+	}
+	slices.SortStableFunc(res, func(a, b domain.KnowledgeChunk) int {
+		return cmp.Compare(scores[b.ID], scores[a.ID])
+	})
+	return res
+}
+
+// diversify picks up to limit chunks from the ranked candidates by maximal marginal relevance, trading the relevance
+// of a chunk for its dissimilarity to the chunks already picked. Relevance is the similarity to the query, one minus
+// the distance, when every candidate has a distance and no reranker reordered them. Otherwise, as for keyword matches,
+// it is given by the position in the ranking. A lambda of 1 keeps the ranking as it is. When maxPerDocument is set,
+// chunks of documents that already have as many picked are skipped.
+func diversify(candidates []domain.KnowledgeChunk, lambda float64, maxPerDocument int, limit int,
+	reranked bool) []domain.KnowledgeChunk {
+	picked := make([]domain.KnowledgeChunk, 0, min(limit, len(candidates)))
+	used := make([]bool, len(candidates))
+	perDocument := make(map[string]int)
+	byDistance := lambda < 1 && !reranked && !slices.ContainsFunc(candidates, func(item domain.KnowledgeChunk) bool {
+		return item.Distance == nil
+	})
+	relevance := make([]float64, len(candidates))
+	for i, candidate := range candidates {
+		if byDistance {
+			relevance[i] = 1 - *candidate.Distance
+		} else {
+			relevance[i] = 1 - float64(i)/float64(len(candidates))
+		}
+	}
+	// similarity holds the highest similarity of each candidate to the chunks picked so far
+	similarity := make([]float64, len(candidates))
+	for len(picked) < limit {
+		best, bestScore := -1, math.Inf(-1)
+		for i, candidate := range candidates {
+			if used[i] || (maxPerDocument > 0 && perDocument[candidate.Document] >= maxPerDocument) {
+				continue
+			}
+			if score := lambda*relevance[i] - (1-lambda)*similarity[i]; score > bestScore {
+				best, bestScore = i, score
+			}
+		}
+		if best < 0 {
+			break
+		}
+		used[best] = true
+		picked = append(picked, candidates[best])
+		perDocument[candidates[best].Document]++
+		if lambda == 1 {
+			continue
+		}
+		for i := range candidates {
+			if !used[i] {
+				similarity[i] = max(similarity[i],
+					cosineSimilarity(candidates[i].Embedding, candidates[best].Embedding))
+			}
+		}
+	}
+	return picked
+}
+
+// cosineSimilarity compares two normalized vectors
+func cosineSimilarity(a pgvector.Vector, b pgvector.Vector) float64 {
+	var dot float64
+	for i, value := range a.Slice() {
+		dot += float64(value) * float64(b.Slice()[i])
+	}
+	return dot
+}
@@ -0,0 +1,152 @@
+/*
+ * Copyright (C) 2026 Simone Pezzano
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package services
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/pgvector/pgvector-go"
+	"github.com/samber/lo"
+	"github.com/theirish81/meta/internal/config"
+	"github.com/theirish81/meta/internal/persistence/domain"
+)
+
+// rankedChunk makes a search result identified by its name, with an optional embedding
+func rankedChunk(name string, document string, embedding ...float32) domain.KnowledgeChunk {
+	return domain.KnowledgeChunk{ID: uuid.NewSHA1(uuid.Nil, []byte(name)), Chunk: name, Document: document,
+		Embedding: pgvector.NewVector(embedding)}
+}
+
+func chunkNames(chunks []domain.KnowledgeChunk) []string {
+	return lo.Map(chunks, func(item domain.KnowledgeChunk, _ int) string {
+		return item.Chunk
+	})
+}
+
+func TestSearchOptionsResolve(t *testing.T) {
+	setConfig(t, func(c *config.Config) {
+		c.SearchMaxOffset = 100
+	})
+	tests := []struct {
+		name    string
+		options SearchOptions
+		want    SearchOptions
+		wantErr bool
+	}{
+		{name: "defaults", want: SearchOptions{Limit: 10, MaxDistance: lo.ToPtr(0.5)}},
+		{name: "limit lowered to the cap", options: SearchOptions{Limit: 80, Offset: 20},
+			want: SearchOptions{Limit: 50, Offset: 20, MaxDistance: lo.ToPtr(0.5)}},
+		{name: "distance override", options: SearchOptions{Limit: 5, MaxDistance: lo.ToPtr(1.5)},
+			want: SearchOptions{Limit: 5, MaxDistance: lo.ToPtr(1.5)}},
+		{name: "negative limit", options: SearchOptions{Limit: -1}, wantErr: true},
+		{name: "negative offset", options: SearchOptions{Offset: -1}, wantErr: true},
+		{name: "offset above the maximum", options: SearchOptions{Offset: 101}, wantErr: true},
+		{name: "distance out of range", options: SearchOptions{MaxDistance: lo.ToPtr(2.5)}, wantErr: true},
+		{name: "lambda out of range", options: SearchOptions{MMRLambda: lo.ToPtr(1.5)}, wantErr: true},
+		{name: "negative cap per document", options: SearchOptions{MaxPerDocument: lo.ToPtr(-1)}, wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := test.options.resolve(10, 50, 0.5)
+			if test.wantErr {
+				if !errors.Is(err, ErrInvalidSearch) {
+					t.Fatalf("got error %v, want %v", err, ErrInvalidSearch)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestFuseRanks(t *testing.T) {
+	a, b, c, d := rankedChunk("a", "x"), rankedChunk("b", "x"), rankedChunk("c", "x"), rankedChunk("d", "x")
+	tests := []struct {
+		name      string
+		byVector  []domain.KnowledgeChunk
+		byKeyword []domain.KnowledgeChunk
+		weight    float64
+		want      []string
+	}{
+		{name: "no results", want: []string{}},
+		{name: "vector only", byVector: []domain.KnowledgeChunk{a, b}, weight: 0.5, want: []string{"a", "b"}},
+		{name: "keyword only", byKeyword: []domain.KnowledgeChunk{b, a}, weight: 0.5, want: []string{"b", "a"}},
+		{name: "found by both ranks first", byVector: []domain.KnowledgeChunk{a, b},
+			byKeyword: []domain.KnowledgeChunk{b, c}, weight: 0.5, want: []string{"b", "a", "c"}},
+		{name: "full vector weight", byVector: []domain.KnowledgeChunk{a, b},
+			byKeyword: []domain.KnowledgeChunk{c, b}, weight: 1, want: []string{"a", "b", "c"}},
+		{name: "full keyword weight", byVector: []domain.KnowledgeChunk{a, b},
+			byKeyword: []domain.KnowledgeChunk{c, d}, weight: 0, want: []string{"c", "d", "a", "b"}},
+		{name: "weight favours the vector ranks", byVector: []domain.KnowledgeChunk{a, b},
+			byKeyword: []domain.KnowledgeChunk{c, d}, weight: 0.7, want: []string{"a", "b", "c", "d"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := chunkNames(fuseRanks(test.byVector, test.byKeyword, test.weight))
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestDiversify(t *testing.T) {
+	candidates := []domain.KnowledgeChunk{
+		rankedChunk("a", "x", 1, 0),
+		rankedChunk("b", "x", 1, 0),
+		rankedChunk("c", "y", 0, 1),
+		rankedChunk("d", "y", 0.6, 0.8),
+	}
+	// c ranks above d, but lies much farther from the query
+	distances := []float64{0.1, 0.15, 0.9, 0.2}
+	for i := range candidates {
+		candidates[i].Distance = &distances[i]
+	}
+	tests := []struct {
+		name           string
+		lambda         float64
+		maxPerDocument int
+		limit          int
+		reranked       bool
+		want           []string
+	}{
+		{name: "keeps the ranking", lambda: 1, limit: 3, want: []string{"a", "b", "c"}},
+		{name: "limit above the candidates", lambda: 1, limit: 10, want: []string{"a", "b", "c", "d"}},
+		{name: "skips duplicates by distance", lambda: 0.5, limit: 2, want: []string{"a", "d"}},
+		{name: "skips duplicates by rank once reranked", lambda: 0.5, limit: 2, reranked: true,
+			want: []string{"a", "c"}},
+		{name: "caps chunks per document", lambda: 1, maxPerDocument: 1, limit: 4, want: []string{"a", "c"}},
+		{name: "zero limit", lambda: 1, limit: 0, want: []string{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := chunkNames(diversify(candidates, test.lambda, test.maxPerDocument, test.limit, test.reranked))
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -28,9 +28,15 @@ import (
 
 type ServiceRegistry struct {
 	EmbeddingService     EmbeddingService
+	EmbeddingBreaker     *CircuitBreaker
+	EmbeddingCache       *CachedEmbeddingService
+	EmbeddingDimensions  int
+	Reranker             Reranker
+	RerankBreaker        *CircuitBreaker
 	RecipeService        *RecipeService
 	KnowledgeBaseService *KnowledgeBaseService
 	ObjectService        *ObjectService
+	JobService           *JobService
 }
 
 var Services ServiceRegistry
@@ -39,16 +45,11 @@ func Init() error {
 	if err := connection.InitConnection(config.Instance.DatabaseURL, logger.Info); err != nil {
 		return err
 	}
-	var err error
-	switch config.Instance.EmbeddingService {
-	case "ollama":
-		Services.EmbeddingService = NewOllamaService(config.Instance.OllamaBaseURL)
-	case "gemini":
-		Services.EmbeddingService, err = NewGeminiService()
-	default:
-		err = errors.New("embedding service not selected")
+	if err := InitEmbedding(); err != nil {
+		return err
 	}
-	if err != nil {
+	initReranker()
+	if err := initTokenizers(); err != nil {
 		return err
 	}
 
@@ -69,5 +70,64 @@ func Init() error {
 		return err
 	}
 	Services.ObjectService = objectService
+
+	jobService := NewJobService()
+	if err := jobService.InitTables(context.Background()); err != nil {
+		return err
+	}
+	Services.JobService = jobService
 	return nil
 }
+
+// InitEmbedding assembles the embedding service chain (provider, retries, cache) and resolves the vector dimension.
+// It expects the database connection to be initialized.
+func InitEmbedding() error {
+	var err error
+	var embeddingService EmbeddingService
+	switch config.Instance.EmbeddingService {
+	case "ollama":
+		embeddingService = NewOllamaService(config.Instance.OllamaBaseURL, config.Instance.OllamaBatchSize,
+			config.Instance.OllamaConcurrency)
+	case "gemini":
+		embeddingService, err = NewGeminiService()
+	case "openai":
+		embeddingService = NewOpenAIService(config.Instance.OpenAIBaseURL, config.Instance.OpenAIAPIKey,
+			config.Instance.OpenAIBatchSize)
+	default:
+		err = errors.New("embedding service not selected")
+	}
+	if err != nil {
+		return err
+	}
+	Services.EmbeddingBreaker = NewCircuitBreaker(config.Instance.EmbeddingService,
+		config.Instance.EmbeddingBreakerThreshold, config.Instance.EmbeddingBreakerCooldown)
+	resilientService := NewResilientEmbeddingService(embeddingService, Services.EmbeddingBreaker,
+		config.Instance.EmbeddingMaxRetries, config.Instance.EmbeddingRetryDelay, config.Instance.EmbeddingRetryMaxDelay)
+
+	cacheService := NewEmbeddingCacheService()
+	if err := cacheService.InitTables(context.Background()); err != nil {
+		return err
+	}
+	Services.EmbeddingCache = NewCachedEmbeddingService(resilientService, cacheService,
+		config.Instance.EmbeddingService, config.Instance.EmbeddingModel)
+	Services.EmbeddingService = Services.EmbeddingCache
+	Services.EmbeddingDimensions, err = resolveDimensions(context.Background(), Services.EmbeddingService)
+	return err
+}
+
+// initReranker sets up the optional reranker. Reranking is best effort, so its failures count towards a breaker of
+// its own and never towards the embedding one
+func initReranker() {
+	switch config.Instance.RerankService {
+	case "ollama":
+		Services.Reranker = NewOllamaReranker(config.Instance.RerankBaseURL, config.Instance.RerankModel,
+			config.Instance.OllamaConcurrency)
+	case "tei", "openai":
+		Services.Reranker = NewRerankAPIService(config.Instance.RerankService, config.Instance.RerankBaseURL,
+			config.Instance.RerankAPIKey, config.Instance.RerankModel)
+	default:
+		return
+	}
+	Services.RerankBreaker = NewCircuitBreaker("reranker", config.Instance.RerankBreakerThreshold,
+		config.Instance.RerankBreakerCooldown)
+}
@@ -0,0 +1,59 @@
+/*
+ * Copyright (C) 2026 Simone Pezzano
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+// standIn stands in for the API of a provider. It answers 418 to the requests a test does not expect, so that the
+// errors the test provokes are not mistaken for the ones caused by a wrong request.
+type standIn struct {
+	*httptest.Server
+	expected atomic.Bool
+}
+
+// newStandIn starts a stand-in that decodes the JSON body of every request and answers with respond the ones expect
+// accepts. The stand-in is closed at the end of the test.
+func newStandIn[T any](t *testing.T, expect func(r *http.Request, request T) bool,
+	respond func(w http.ResponseWriter, request T)) *standIn {
+	s := &standIn{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request T
+		if json.NewDecoder(r.Body).Decode(&request) != nil || !expect(r, request) {
+			w.WriteHeader(http.StatusTeapot)
+			return
+		}
+		s.expected.Store(true)
+		respond(w, request)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// requireExpected stops the test unless the stand-in received an expected request
+func (s *standIn) requireExpected(t *testing.T) {
+	t.Helper()
+	if !s.expected.Load() {
+		t.Fatal("the stand-in received no expected request")
+	}
+}
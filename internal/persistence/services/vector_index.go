@@ -0,0 +1,244 @@
+/*
+ * Copyright (C) 2026 Simone Pezzano
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/lib/pq"
+	"github.com/theirish81/meta/internal/config"
+	"github.com/theirish81/meta/internal/persistence/connection"
+	"gorm.io/gorm"
+)
+
+const (
+	// maxVectorIndexDimensions is the largest vector pgvector can index at full precision
+	maxVectorIndexDimensions = 2000
+	// maxHalfvecIndexDimensions is the largest vector pgvector can index at half precision
+	maxHalfvecIndexDimensions = 4000
+)
+
+// iterativeScan is the HNSW iterative scan mode of searches: the configured one, when pgvector supports it
+var iterativeScan = "off"
+
+// detectIterativeScan enables the configured iterative scan mode, which requires pgvector 0.8
+func detectIterativeScan(db *gorm.DB) error {
+	if config.Instance.HnswIterativeScan == "off" || iterativeScan != "off" {
+		return nil
+	}
+	var version string
+	if err := db.Raw("SELECT extversion FROM pg_extension WHERE extname = 'vector'").Scan(&version).Error; err != nil {
+		return err
+	}
+	var major, minor int
+	if _, err := fmt.Sscanf(version, "%d.%d", &major, &minor); err == nil && (major > 0 || minor >= 8) {
+		iterativeScan = config.Instance.HnswIterativeScan
+		return nil
+	}
+	log.Printf("pgvector %s does not support iterative scans: searches filtered down to a small share of the "+
+		"rows may return fewer results than requested. Upgrade pgvector to 0.8 or later", version)
+	return nil
+}
+
+func vectorIndexName(table string) string {
+	return table + "_embedding_idx"
+}
+
+// vectorIndexed tells whether vectors of the given dimension get an index
+func vectorIndexed(dimensions int) bool {
+	return config.Instance.VectorIndex != "none" && dimensions <= maxHalfvecIndexDimensions
+}
+
+// indexedEmbedding returns the expression the index is built on, and its operator class. Vectors too large to be
+// indexed at full precision are indexed as half precision vectors.
+func indexedEmbedding(dimensions int) (string, string) {
+	if dimensions > maxVectorIndexDimensions {
+		return fmt.Sprintf("(embedding::halfvec(%d))", dimensions), "halfvec_cosine_ops"
+	}
+	return "embedding", "vector_cosine_ops"
+}
+
+// vectorIndexOptions returns the storage parameters of the configured index, as Postgres reports them
+func vectorIndexOptions() []string {
+	if config.Instance.VectorIndex == "ivfflat" {
+		return []string{fmt.Sprintf("lists=%d", config.Instance.IvfflatLists)}
+	}
+	return []string{fmt.Sprintf("m=%d", config.Instance.HnswM),
+		fmt.Sprintf("ef_construction=%d", config.Instance.HnswEfConstruction)}
+}
+
+// distanceExpression returns the cosine distance between the embedding column and the query vector, in the form
+// that matches the index so that the planner can use it
+func distanceExpression() string {
+	dimensions := Services.EmbeddingDimensions
+	if vectorIndexed(dimensions) && dimensions > maxVectorIndexDimensions {
+		return fmt.Sprintf("embedding::halfvec(%d) <=> ?::halfvec(%d)", dimensions, dimensions)
+	}
+	return "embedding <=> ?"
+}
+
+// vectorSearchSettings tunes the index scans of the transaction, for searches retrieving up to limit rows. It must run
+// in the same transaction as the search.
+func vectorSearchSettings(tx *gorm.DB, limit int) error {
+	if !vectorIndexed(Services.EmbeddingDimensions) {
+		return nil
+	}
+	if config.Instance.VectorIndex == "ivfflat" {
+		return tx.Exec(fmt.Sprintf("SET LOCAL ivfflat.probes = %d", config.Instance.IvfflatProbes)).Error
+	}
+	// An HNSW scan returns at most ef_search rows
+	efSearch := min(max(config.Instance.HnswEfSearch, limit), 1000)
+	if err := tx.Exec(fmt.Sprintf("SET LOCAL hnsw.ef_search = %d", efSearch)).Error; err != nil {
+		return err
+	}
+	if iterativeScan != "off" {
+		return tx.Exec("SET LOCAL hnsw.iterative_scan = " + iterativeScan).Error
+	}
+	return nil
+}
+
+// createVectorIndex creates the named index on the embedding column of a table, as configured. Concurrent creation
+// does not block writes, but cannot run in a transaction.
+func createVectorIndex(db *gorm.DB, table string, index string, dimensions int, concurrently bool) error {
+	if !vectorIndexed(dimensions) {
+		if config.Instance.VectorIndex != "none" {
+			log.Printf("%s holds %d-dimensional embeddings and pgvector cannot index more than %d: searches will "+
+				"scan the table", table, dimensions, maxHalfvecIndexDimensions)
+		}
+		return nil
+	}
+	expression, operators := indexedEmbedding(dimensions)
+	mode := ""
+	if concurrently {
+		mode = "CONCURRENTLY "
+	}
+	return db.Exec(fmt.Sprintf("CREATE INDEX %sIF NOT EXISTS %s ON %s USING %s (%s %s) WITH (%s)", mode, index,
+		table, config.Instance.VectorIndex, expression, operators,
+		strings.Join(vectorIndexOptions(), ", "))).Error
+}
+
+func dropVectorIndex(db *gorm.DB, table string, concurrently bool) error {
+	mode := ""
+	if concurrently {
+		mode = "CONCURRENTLY "
+	}
+	return db.Exec(fmt.Sprintf("DROP INDEX %sIF EXISTS %s", mode, vectorIndexName(table))).Error
+}
+
+// ensureVectorIndex creates the index of a table when missing, and drops it when indexes are disabled. An existing
+// index built with different settings is left in place, as rebuilding it can take long: `meta index rebuild` does.
+func ensureVectorIndex(ctx context.Context, conn *connection.Connection, table string, dimensions int) error {
+	db := conn.WithContext(ctx)
+	if err := detectIterativeScan(db); err != nil {
+		return err
+	}
+	var existing struct {
+		Method  string
+		Options pq.StringArray
+		Valid   bool
+	}
+	err := db.Raw(`SELECT am.amname AS method, c.reloptions AS options, i.indisvalid AS valid FROM pg_class c
+		JOIN pg_am am ON am.oid = c.relam JOIN pg_index i ON i.indexrelid = c.oid WHERE c.relname = ?`,
+		vectorIndexName(table)).Scan(&existing).Error
+	if err != nil {
+		return err
+	}
+	if existing.Method != "" && (!existing.Valid || !vectorIndexed(dimensions)) {
+		// An invalid index is left behind by an interrupted concurrent build
+		if err = dropVectorIndex(db, table, false); err != nil {
+			return err
+		}
+		existing.Method = ""
+	}
+	if existing.Method == "" {
+		if vectorIndexed(dimensions) {
+			log.Printf("creating the %s index of %s", config.Instance.VectorIndex, table)
+		}
+		return createVectorIndex(db, table, vectorIndexName(table), dimensions, false)
+	}
+	options := slices.Clone(existing.Options)
+	expected := vectorIndexOptions()
+	slices.Sort(options)
+	slices.Sort(expected)
+	if existing.Method != config.Instance.VectorIndex || !slices.Equal(options, expected) {
+		log.Printf("the index of %s is a %s index with %v, while %s with %v is configured. Run `meta index rebuild` "+
+			"to apply the configuration", table, existing.Method, existing.Options, config.Instance.VectorIndex,
+			vectorIndexOptions())
+	}
+	return nil
+}
+
+type VectorIndexService struct {
+	conn *connection.Connection
+}
+
+func NewVectorIndexService() *VectorIndexService {
+	return &VectorIndexService{
+		conn: connection.Conn,
+	}
+}
+
+// Rebuild recreates the index of every table holding embeddings with the current configuration. The new index is
+// built concurrently next to the old one, which searches keep using until the new one replaces it.
+func (s *VectorIndexService) Rebuild(ctx context.Context, progress func(table string)) error {
+	db := s.conn.WithContext(ctx)
+	for _, target := range reindexTargets {
+		progress(target.table)
+		dimensions, err := storedDimensions(db, target.table)
+		if err != nil {
+			return err
+		}
+		if err = rebuildVectorIndex(db, target.table, dimensions); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rebuildVectorIndex concurrently builds the index of a table under a temporary name, then swaps it for the current
+// one. The swap runs in a transaction, which locks the table only for as long as it takes to drop and rename.
+func rebuildVectorIndex(db *gorm.DB, table string, dimensions int) error {
+	if !vectorIndexed(dimensions) {
+		if err := dropVectorIndex(db, table, true); err != nil {
+			return err
+		}
+		return createVectorIndex(db, table, vectorIndexName(table), dimensions, true)
+	}
+	if dimensions < 0 {
+		return fmt.Errorf("the embedding column of %s has no fixed dimension, so it cannot be indexed. Start the "+
+			"service once to size it to the configured model", table)
+	}
+	building := vectorIndexName(table) + "_rebuild"
+	// An interrupted rebuild leaves an invalid index behind
+	if err := db.Exec("DROP INDEX CONCURRENTLY IF EXISTS " + building).Error; err != nil {
+		return err
+	}
+	if err := createVectorIndex(db, table, building, dimensions, true); err != nil {
+		return err
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := dropVectorIndex(tx, table, false); err != nil {
+			return err
+		}
+		return tx.Exec(fmt.Sprintf("ALTER INDEX %s RENAME TO %s", building, vectorIndexName(table))).Error
+	})
+}
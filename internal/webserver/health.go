@@ -0,0 +1,52 @@
+/*
+ * Copyright (C) 2026 Simone Pezzano
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package webserver
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/samber/lo"
+	"github.com/theirish81/meta/internal/persistence/services"
+)
+
+type healthStatus struct {
+	Status         string                  `json:"status"`
+	Embedding      services.BreakerStatus  `json:"embedding"`
+	EmbeddingCache services.CacheStats     `json:"embedding_cache"`
+	Reranker       *services.BreakerStatus `json:"reranker,omitempty"`
+}
+
+// initHealth registers the unauthenticated health endpoint
+func (s Server) initHealth() {
+	s.E.GET("/health", func(c echo.Context) error {
+		status := healthStatus{
+			Status:         "ok",
+			Embedding:      s.Services.EmbeddingBreaker.Status(),
+			EmbeddingCache: s.Services.EmbeddingCache.Stats(),
+		}
+		if s.Services.RerankBreaker != nil {
+			status.Reranker = lo.ToPtr(s.Services.RerankBreaker.Status())
+		}
+		if status.Embedding.State != services.BreakerClosed ||
+			(status.Reranker != nil && status.Reranker.State != services.BreakerClosed) {
+			status.Status = "degraded"
+		}
+		return c.JSON(http.StatusOK, status)
+	})
+}
@@ -21,11 +21,13 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log"
 	"net/http"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"github.com/modelcontextprotocol/go-sdk/auth"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -57,8 +59,17 @@ func (s Server) initMCP() {
 				}
 			}()
 			claims := getMetaClaims(request.GetExtra().TokenInfo.Extra)
-			res, err := services.Services.RecipeService.Search(ctx, claims.Subject, args.Memory, &args.Tag, &args.Q)
-			return toCallResult(edjson.MustCopy[dto.Recipes](res), "recipes"), nil, err
+			res, err := services.Services.RecipeService.Search(ctx, claims.Subject, args.Memory, &args.Q,
+				services.SearchOptions{
+					Tags:        &args.Tag,
+					Limit:       args.Limit,
+					Offset:      args.Offset,
+					MaxDistance: args.MaxDistance,
+				})
+			if err != nil {
+				return nil, nil, toolError(err)
+			}
+			return toCallResult(edjson.MustCopy[dto.Recipes](res), "recipes"), nil, nil
 		})
 
 	mcp.AddTool(mcpServer, toolKnowledgeSearch,
@@ -69,8 +80,25 @@ func (s Server) initMCP() {
 				}
 			}()
 			claims := getMetaClaims(request.GetExtra().TokenInfo.Extra)
-			res, err := services.Services.KnowledgeBaseService.Search(ctx, claims.Subject, args.Memory, &args.Tag, args.Q)
-			return toCallResult(edjson.MustCopy[dto.KnowledgeChunks](res), "knowledge"), nil, err
+			mode, err := services.ParseSearchMode(args.Mode)
+			if err != nil {
+				return nil, nil, err
+			}
+			res, err := services.Services.KnowledgeBaseService.Search(ctx, claims.Subject, args.Memory, args.Q,
+				services.SearchOptions{
+					Tags:           &args.Tag,
+					Mode:           mode,
+					Limit:          args.Limit,
+					Offset:         args.Offset,
+					MaxDistance:    args.MaxDistance,
+					MMRLambda:      args.MMRLambda,
+					MaxPerDocument: args.MaxPerDocument,
+					ContextWindow:  args.ContextWindow,
+				})
+			if err != nil {
+				return nil, nil, toolError(err)
+			}
+			return toCallResult(edjson.MustCopy[dto.KnowledgeChunks](res), "knowledge"), nil, nil
 		})
 	mcp.AddTool(mcpServer, toolKnowledgeMemories,
 		func(ctx context.Context, request *mcp.CallToolRequest, input any) (*mcp.CallToolResult, any, error) {
@@ -83,6 +111,42 @@ func (s Server) initMCP() {
 			res, err := services.Services.KnowledgeBaseService.Memories(ctx, claims.Subject)
 			return toCallResult(res, "knowledge_memories"), nil, err
 		})
+	mcp.AddTool(mcpServer, toolGetDocument,
+		func(ctx context.Context, request *mcp.CallToolRequest, args documentParams) (*mcp.CallToolResult, any,
+			error) {
+			defer func() {
+				if e := recover(); e != nil {
+					log.Println(e)
+				}
+			}()
+			claims := getMetaClaims(request.GetExtra().TokenInfo.Extra)
+			res, err := readDocument(ctx, services.Services.KnowledgeBaseService, claims.Subject, args.Memory,
+				args.Document, services.ContentRange{
+					StartByte: args.StartByte,
+					EndByte:   args.EndByte,
+					StartLine: args.StartLine,
+					EndLine:   args.EndLine,
+				})
+			if err != nil {
+				return nil, nil, err
+			}
+			return toCallResult(res, "document"), nil, nil
+		})
+	mcp.AddTool(mcpServer, toolGetKnowledgeChunks,
+		func(ctx context.Context, request *mcp.CallToolRequest, args chunksParams) (*mcp.CallToolResult, any,
+			error) {
+			defer func() {
+				if e := recover(); e != nil {
+					log.Println(e)
+				}
+			}()
+			claims := getMetaClaims(request.GetExtra().TokenInfo.Extra)
+			res, err := services.Services.KnowledgeBaseService.GetChunks(ctx, claims.Subject, args.IDs)
+			if err != nil {
+				return nil, nil, err
+			}
+			return toCallResult(edjson.MustCopy[dto.KnowledgeChunks](res), "knowledge"), nil, nil
+		})
 	mcp.AddTool(mcpServer, toolObjectMemories,
 		func(ctx context.Context, request *mcp.CallToolRequest, input objectParams) (*mcp.CallToolResult, any, error) {
 			defer func() {
@@ -155,20 +219,56 @@ func toCallResult(data any, rootObjectName string) *mcp.CallToolResult {
 	}}
 }
 
+// toolError rewrites embedding failures into messages an agent can act upon
+func toolError(err error) error {
+	switch {
+	case errors.Is(err, services.ErrProviderUnavailable):
+		return fmt.Errorf("the search service is temporarily unavailable, try again later (%w)", err)
+	case errors.Is(err, services.ErrModelNotFound), errors.Is(err, services.ErrDimensionMismatch),
+		errors.Is(err, services.ErrInvalidEmbedding):
+		return fmt.Errorf("the search service is misconfigured, do not retry and inform the user (%w)", err)
+	default:
+		return err
+	}
+}
+
 func getMetaClaims(m map[string]any) *auth2.MetaClaims {
 	return m["claims"].(*auth2.MetaClaims)
 }
 
 type kbParams struct {
-	Memory string   `json:"memory"`
-	Tag    []string `json:"tag"`
-	Q      string   `json:"q"`
+	Memory         string   `json:"memory"`
+	Tag            []string `json:"tag"`
+	Q              string   `json:"q"`
+	Mode           string   `json:"mode"`
+	Limit          int      `json:"limit"`
+	Offset         int      `json:"offset"`
+	MaxDistance    *float64 `json:"max_distance"`
+	MMRLambda      *float64 `json:"mmr_lambda"`
+	MaxPerDocument *int     `json:"max_per_document"`
+	ContextWindow  int      `json:"context_window"`
+}
+
+type documentParams struct {
+	Memory    string `json:"memory"`
+	Document  string `json:"document"`
+	StartByte *int   `json:"start_byte"`
+	EndByte   *int   `json:"end_byte"`
+	StartLine *int   `json:"start_line"`
+	EndLine   *int   `json:"end_line"`
+}
+
+type chunksParams struct {
+	IDs []uuid.UUID `json:"ids"`
 }
 
 type recipeParams struct {
-	Memory string   `json:"memory"`
-	Tag    []string `json:"tag"`
-	Q      string   `json:"q"`
+	Memory      string   `json:"memory"`
+	Tag         []string `json:"tag"`
+	Q           string   `json:"q"`
+	Limit       int      `json:"limit"`
+	Offset      int      `json:"offset"`
+	MaxDistance *float64 `json:"max_distance"`
 }
 
 type objectParams struct {
@@ -180,7 +280,7 @@ type objectParams struct {
 
 var toolKnowledgeSearch = &mcp.Tool{
 	Name:        "meta_search_knowledge",
-	Description: "searches  knowledge. Knowledge records contain knowledge that is useful as-is to the user. Call this for most topics",
+	Description: "searches  knowledge. Knowledge records contain knowledge that is useful as-is to the user. Call this for most topics. Each knowledge record reports its id, document and ordinal: cite them when answering from it",
 	InputSchema: &jsonschema.Schema{
 		Type:     "object",
 		Required: []string{"memory", "tag", "q"},
@@ -200,6 +300,100 @@ var toolKnowledgeSearch = &mcp.Tool{
 				Type:        "string",
 				Description: "the user prompt that led to this tool execution",
 			},
+			"mode": {
+				Type:        "string",
+				Description: "how knowledge is matched. Use `keyword` to find exact identifiers, error codes or log lines, `hybrid` when the prompt mixes them with a description, and `vector` (the default) otherwise",
+				Enum:        []any{"vector", "keyword", "hybrid"},
+			},
+			"limit": {
+				Type:        "integer",
+				Description: "the maximum number of knowledge records to return. Raise it when the first results are not enough",
+				Minimum:     jsonschema.Ptr(1.0),
+			},
+			"offset": {
+				Type:        "integer",
+				Description: "the number of knowledge records to skip, to get further results of the same search",
+				Minimum:     jsonschema.Ptr(0.0),
+			},
+			"max_distance": {
+				Type:        "number",
+				Description: "the cosine distance beyond which knowledge records are discarded, between 0 and 2. Lower it for precision, raise it for recall",
+				Minimum:     jsonschema.Ptr(0.0),
+				Maximum:     jsonschema.Ptr(2.0),
+			},
+			"mmr_lambda": {
+				Type:        "number",
+				Description: "diversifies the results, between 0 and 1. 1 ranks by relevance only, lower values such as 0.5 favour knowledge records unlike the ones already returned. Lower it when results repeat themselves",
+				Minimum:     jsonschema.Ptr(0.0),
+				Maximum:     jsonschema.Ptr(1.0),
+			},
+			"max_per_document": {
+				Type:        "integer",
+				Description: "the maximum number of knowledge records from the same document, to get results from several sources. 0 means no cap",
+				Minimum:     jsonschema.Ptr(0.0),
+			},
+			"context_window": {
+				Type:        "integer",
+				Description: "the number of neighbouring knowledge records, before and after each result, merged into its context. Raise it to 1 or 2 when results look cut short",
+				Minimum:     jsonschema.Ptr(0.0),
+			},
+		},
+	},
+}
+var toolGetDocument = &mcp.Tool{
+	Name:        "meta_get_document",
+	Description: "reads a knowledge document, whole or in part. Call this when a knowledge record returned by meta_search_knowledge is relevant but incomplete, to read more of its document. The result reports the total_bytes and total_lines of the document: read large documents a range at a time",
+	InputSchema: &jsonschema.Schema{
+		Type:     "object",
+		Required: []string{"memory", "document"},
+		Properties: map[string]*jsonschema.Schema{
+			"memory": {
+				Type:        "string",
+				Description: "the memory slot of the document",
+			},
+			"document": {
+				Type:        "string",
+				Description: "the name of the document, as reported by the knowledge records",
+			},
+			"start_byte": {
+				Type:        "integer",
+				Description: "the first byte to read, from 0. The start_offset of a knowledge record is a byte offset that can be used here",
+				Minimum:     jsonschema.Ptr(0.0),
+			},
+			"end_byte": {
+				Type:        "integer",
+				Description: "the byte to stop reading before. The end_offset of a knowledge record is a byte offset that can be used here",
+				Minimum:     jsonschema.Ptr(0.0),
+			},
+			"start_line": {
+				Type:        "integer",
+				Description: "the first line to read, from 1. Lines cannot be combined with bytes",
+				Minimum:     jsonschema.Ptr(1.0),
+			},
+			"end_line": {
+				Type:        "integer",
+				Description: "the last line to read",
+				Minimum:     jsonschema.Ptr(1.0),
+			},
+		},
+	},
+}
+var toolGetKnowledgeChunks = &mcp.Tool{
+	Name:        "meta_get_knowledge_chunks",
+	Description: "fetches knowledge records by id. Call this to read again the knowledge records cited in an earlier answer, without searching",
+	InputSchema: &jsonschema.Schema{
+		Type:     "object",
+		Required: []string{"ids"},
+		Properties: map[string]*jsonschema.Schema{
+			"ids": {
+				Type:        "array",
+				Description: "the ids of the knowledge records, as returned by meta_search_knowledge",
+				MinItems:    jsonschema.Ptr(1),
+				Items: &jsonschema.Schema{
+					Type:   "string",
+					Format: "uuid",
+				},
+			},
 		},
 	},
 }
@@ -234,6 +428,22 @@ var toolRecipeSearch = &mcp.Tool{
 				Type:        "string",
 				Description: "the user prompt that led to this tool execution",
 			},
+			"limit": {
+				Type:        "integer",
+				Description: "the maximum number of recipes to return. Raise it when the first results are not enough",
+				Minimum:     jsonschema.Ptr(1.0),
+			},
+			"offset": {
+				Type:        "integer",
+				Description: "the number of recipes to skip, to get further results of the same search",
+				Minimum:     jsonschema.Ptr(0.0),
+			},
+			"max_distance": {
+				Type:        "number",
+				Description: "the cosine distance beyond which recipes are discarded, between 0 and 2. Lower it for precision, raise it for recall",
+				Minimum:     jsonschema.Ptr(0.0),
+				Maximum:     jsonschema.Ptr(2.0),
+			},
 		},
 	},
 }
@@ -0,0 +1,52 @@
+/*
+ * Copyright (C) 2026 Simone Pezzano
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package webserver
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+	"github.com/theirish81/meta/internal/dto"
+	"github.com/theirish81/meta/internal/persistence/domain"
+)
+
+func (s Server) GetJob(ctx echo.Context, id openapi_types.UUID) error {
+	job, err := s.Services.JobService.Get(ctx.Request().Context(), MustGetUser(ctx).Subject, id)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, jobToDTO(job))
+}
+
+func jobToDTO(job domain.IngestionJob) dto.IngestionJob {
+	return dto.IngestionJob{
+		Id:              job.ID,
+		Memory:          job.Memory,
+		Document:        job.Document,
+		State:           dto.IngestionJobState(job.State),
+		Attempts:        job.Attempts,
+		ChunksToEmbed:   job.ChunksToEmbed,
+		ChunksEmbedded:  job.ChunksEmbedded,
+		Error:           job.Error,
+		DocumentVersion: job.DocumentVersion,
+		CreatedAt:       job.CreatedAt,
+		StartedAt:       job.StartedAt,
+		FinishedAt:      job.FinishedAt,
+	}
+}
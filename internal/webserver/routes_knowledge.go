@@ -18,30 +18,215 @@
 package webserver
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
 	"net/http"
+	"strings"
 
 	"github.com/labstack/echo/v4"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+	"github.com/samber/lo"
 	"github.com/theirish81/edjson"
+	"github.com/theirish81/meta/internal/config"
 	"github.com/theirish81/meta/internal/dto"
+	"github.com/theirish81/meta/internal/extraction"
+	"github.com/theirish81/meta/internal/persistence/domain"
+	"github.com/theirish81/meta/internal/persistence/services"
 )
 
 func (s Server) SearchKb(ctx echo.Context, memory string, params dto.SearchKbParams) error {
+	mode, err := services.ParseSearchMode(string(lo.FromPtr(params.Mode)))
+	if err != nil {
+		return err
+	}
 	kbs, err := s.Services.KnowledgeBaseService.Search(ctx.Request().Context(), MustGetUser(ctx).Subject, memory,
-		params.Tag, params.Q)
+		params.Q, services.SearchOptions{
+			Tags:           params.Tag,
+			Mode:           mode,
+			Limit:          lo.FromPtr(params.Limit),
+			Offset:         lo.FromPtr(params.Offset),
+			MaxDistance:    params.MaxDistance,
+			MMRLambda:      params.MmrLambda,
+			MaxPerDocument: params.MaxPerDocument,
+			ContextWindow:  lo.FromPtr(params.ContextWindow),
+		})
 	return edjson.JSON[dto.KnowledgeChunks](ctx, http.StatusOK, kbs, err)
 }
 
+func (s Server) GetKbChunks(ctx echo.Context, params dto.GetKbChunksParams) error {
+	chunks, err := s.Services.KnowledgeBaseService.GetChunks(ctx.Request().Context(), MustGetUser(ctx).Subject,
+		params.Id)
+	return edjson.JSON[dto.KnowledgeChunks](ctx, http.StatusOK, chunks, err)
+}
+
+func (s Server) GetKbChunk(ctx echo.Context, id openapi_types.UUID) error {
+	chunk, err := s.Services.KnowledgeBaseService.GetChunk(ctx.Request().Context(), MustGetUser(ctx).Subject, id)
+	return edjson.JSON[dto.KnowledgeChunk](ctx, http.StatusOK, chunk, err)
+}
+
 func (s Server) SubmitDocument(ctx echo.Context, memory string, document string) error {
-	dx := dto.Document{}
-	if err := ctx.Bind(&dx); err != nil {
+	dx, format, file, err := documentContent(ctx, document)
+	if err != nil {
+		return err
+	}
+	var chunking *domain.ChunkingSettings
+	if dx.Chunking != nil {
+		chunking = lo.ToPtr(chunkingFromDTO(*dx.Chunking))
+	}
+	job, err := s.Services.JobService.EnqueueDocument(ctx.Request().Context(), MustGetUser(ctx).Subject, memory,
+		document, dx.Tags, dx.Content, format, file, chunking)
+	if err != nil {
+		return err
+	}
+	ctx.Response().Header().Set(echo.HeaderLocation, "/api/v1/jobs/"+job.ID.String())
+	return ctx.JSON(http.StatusAccepted, jobToDTO(job))
+}
+
+// documentContent reads a document from a JSON body, or extracts its text from a file uploaded as
+// multipart/form-data. The file is returned as well, to be kept along with the document.
+func documentContent(ctx echo.Context, document string) (dto.Document, extraction.Format, *services.SourceFile,
+	error) {
+	dx := dto.Document{
+		Tags: make([]string, 0),
+	}
+	if !strings.HasPrefix(ctx.Request().Header.Get(echo.HeaderContentType), echo.MIMEMultipartForm) {
+		if err := ctx.Bind(&dx); err != nil {
+			return dx, "", nil, err
+		}
+		return dx, extraction.FormatOf(document), nil, nil
+	}
+	ctx.Request().Body = http.MaxBytesReader(ctx.Response(), ctx.Request().Body,
+		int64(config.Instance.UploadMaxMB)<<20)
+	header, err := ctx.FormFile("file")
+	if err != nil {
+		var maxBytesError *http.MaxBytesError
+		if errors.As(err, &maxBytesError) {
+			return dx, "", nil, echo.NewHTTPError(http.StatusRequestEntityTooLarge,
+				fmt.Sprintf("files cannot exceed %dMB", config.Instance.UploadMaxMB))
+		}
+		return dx, "", nil, echo.NewHTTPError(http.StatusBadRequest, "the file field is required")
+	}
+	file, err := header.Open()
+	if err != nil {
+		return dx, "", nil, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return dx, "", nil, err
+	}
+	text, format, err := extraction.Extract(data, document, header.Filename)
+	if err != nil {
+		return dx, "", nil, err
+	}
+	dx.Content = text
+	if form, err := ctx.MultipartForm(); err == nil {
+		dx.Tags = append(dx.Tags, form.Value["tags"]...)
+	}
+	if chunking := ctx.FormValue("chunking"); chunking != "" {
+		if err := json.Unmarshal([]byte(chunking), &dx.Chunking); err != nil {
+			return dx, "", nil, echo.NewHTTPError(http.StatusBadRequest, "the chunking field is not valid JSON")
+		}
+	}
+	// The media type the client declared is not trusted, as the file is served back as such
+	return dx, format, &services.SourceFile{Name: header.Filename, MediaType: extraction.MediaType(data, document,
+		header.Filename), Data: data}, nil
+}
+
+func (s Server) GetKbSettings(ctx echo.Context, memory string) error {
+	chunking, err := s.Services.KnowledgeBaseService.ChunkingSettings(ctx.Request().Context(), MustGetUser(ctx).Subject,
+		memory)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, dto.MemorySettings{
+		Chunking: lo.ToPtr(chunkingToDTO(chunking)),
+	})
+}
+
+func (s Server) UpdateKbSettings(ctx echo.Context, memory string) error {
+	body := dto.MemorySettings{}
+	if err := ctx.Bind(&body); err != nil {
 		return err
 	}
-	err := s.Services.KnowledgeBaseService.RecordDocument(ctx.Request().Context(), MustGetUser(ctx).Subject, memory,
-		document, dx.Tags, dx.Content)
+	chunking := domain.ChunkingSettings{}
+	if body.Chunking != nil {
+		chunking = chunkingFromDTO(*body.Chunking)
+	}
+	err := s.Services.KnowledgeBaseService.UpdateChunkingSettings(ctx.Request().Context(), MustGetUser(ctx).Subject,
+		memory, chunking)
 	if err != nil {
 		return err
 	}
-	return ctx.NoContent(http.StatusOK)
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (s Server) GetDocument(ctx echo.Context, memory string, document string, params dto.GetDocumentParams) error {
+	contentRange := services.ContentRange{
+		StartByte: params.StartByte,
+		EndByte:   params.EndByte,
+		StartLine: params.StartLine,
+		EndLine:   params.EndLine,
+	}
+	if !lo.FromPtr(params.Original) {
+		res, err := readDocument(ctx.Request().Context(), s.Services.KnowledgeBaseService, MustGetUser(ctx).Subject,
+			memory, document, contentRange)
+		if err != nil {
+			return err
+		}
+		return ctx.JSON(http.StatusOK, res)
+	}
+	if contentRange != (services.ContentRange{}) {
+		return echo.NewHTTPError(http.StatusBadRequest, "ranges do not apply to the original file")
+	}
+	_, content, err := s.Services.KnowledgeBaseService.GetDocument(ctx.Request().Context(), MustGetUser(ctx).Subject,
+		memory, document, true)
+	if err != nil {
+		return err
+	}
+	ctx.Response().Header().Set(echo.HeaderXContentTypeOptions, "nosniff")
+	if content.FileName == nil {
+		return ctx.Blob(http.StatusOK, echo.MIMETextPlainCharsetUTF8, []byte(content.Text))
+	}
+	ctx.Response().Header().Set(echo.HeaderContentDisposition, mime.FormatMediaType("attachment",
+		map[string]string{"filename": *content.FileName}))
+	mediaType := lo.FromPtr(content.MediaType)
+	if !extraction.KnownMediaType(mediaType) {
+		// Files recorded before their type was detected carry the one their client declared
+		mediaType = echo.MIMEOctetStream
+	}
+	return ctx.Blob(http.StatusOK, mediaType, content.File)
+}
+
+// readDocument returns the text of a document within a range
+func readDocument(ctx context.Context, service *services.KnowledgeBaseService, ownerID string, memory string,
+	name string, contentRange services.ContentRange) (dto.DocumentContent, error) {
+	document, content, err := service.GetDocument(ctx, ownerID, memory, name, false)
+	if err != nil {
+		return dto.DocumentContent{}, err
+	}
+	slice, err := contentRange.Slice(content.Text)
+	if err != nil {
+		return dto.DocumentContent{}, err
+	}
+	return dto.DocumentContent{
+		Document:   documentToDTO(document),
+		Content:    slice.Text,
+		FileName:   content.FileName,
+		MediaType:  content.MediaType,
+		StartByte:  slice.StartByte,
+		EndByte:    slice.EndByte,
+		StartLine:  slice.StartLine,
+		EndLine:    slice.EndLine,
+		TotalBytes: slice.TotalBytes,
+		TotalLines: slice.TotalLines,
+	}, nil
 }
 
 func (s Server) DeleteDocument(ctx echo.Context, memory string, document string) error {
@@ -57,7 +242,9 @@ func (s Server) ListDocuments(ctx echo.Context, memory string) error {
 	if err != nil {
 		return err
 	}
-	return ctx.JSON(http.StatusOK, docs)
+	return ctx.JSON(http.StatusOK, lo.Map(docs, func(document domain.Document, _ int) dto.KnowledgeDocument {
+		return documentToDTO(document)
+	}))
 }
 
 func (s Server) ListKbMemories(ctx echo.Context) error {
@@ -67,3 +254,40 @@ func (s Server) ListKbMemories(ctx echo.Context) error {
 	}
 	return ctx.JSON(http.StatusOK, memories)
 }
+
+func (s Server) ListKbEmbeddingModels(ctx echo.Context) error {
+	models, err := s.Services.KnowledgeBaseService.EmbeddingModels(ctx.Request().Context(), MustGetUser(ctx).Subject)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, models)
+}
+
+func documentToDTO(document domain.Document) dto.KnowledgeDocument {
+	res := edjson.MustCopy[dto.KnowledgeDocument](document)
+	res.Chunking = chunkingToDTO(document.Chunking.Data())
+	return res
+}
+
+// chunkingToDTO converts chunking settings, leaving the unset fields out
+func chunkingToDTO(chunking domain.ChunkingSettings) dto.ChunkingSettings {
+	return dto.ChunkingSettings{
+		Strategy:             lo.EmptyableToPtr(dto.ChunkingSettingsStrategy(chunking.Strategy)),
+		Size:                 chunking.Size,
+		Overlap:              chunking.Overlap,
+		Unit:                 lo.EmptyableToPtr(dto.ChunkingSettingsUnit(chunking.Unit)),
+		Encoding:             lo.EmptyableToPtr(dto.ChunkingSettingsEncoding(chunking.Encoding)),
+		BreakpointPercentile: chunking.BreakpointPercentile,
+	}
+}
+
+func chunkingFromDTO(chunking dto.ChunkingSettings) domain.ChunkingSettings {
+	return domain.ChunkingSettings{
+		Strategy:             string(lo.FromPtr(chunking.Strategy)),
+		Size:                 chunking.Size,
+		Overlap:              chunking.Overlap,
+		Unit:                 string(lo.FromPtr(chunking.Unit)),
+		Encoding:             string(lo.FromPtr(chunking.Encoding)),
+		BreakpointPercentile: chunking.BreakpointPercentile,
+	}
+}
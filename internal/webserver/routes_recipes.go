@@ -22,13 +22,21 @@ import (
 
 	"github.com/labstack/echo/v4"
 	openapi_types "github.com/oapi-codegen/runtime/types"
+	"github.com/samber/lo"
 	"github.com/theirish81/edjson"
 	"github.com/theirish81/meta/internal/dto"
 	"github.com/theirish81/meta/internal/persistence/domain"
+	"github.com/theirish81/meta/internal/persistence/services"
 )
 
 func (s Server) SearchRecipes(ctx echo.Context, memory string, params dto.SearchRecipesParams) error {
-	meta, err := s.Services.RecipeService.Search(ctx.Request().Context(), MustGetUser(ctx).Subject, memory, params.Tag, params.Q)
+	meta, err := s.Services.RecipeService.Search(ctx.Request().Context(), MustGetUser(ctx).Subject, memory, params.Q,
+		services.SearchOptions{
+			Tags:        params.Tag,
+			Limit:       lo.FromPtr(params.Limit),
+			Offset:      lo.FromPtr(params.Offset),
+			MaxDistance: params.MaxDistance,
+		})
 	return edjson.JSON[dto.Recipes](ctx, http.StatusOK, meta, err)
 }
 
@@ -40,6 +48,14 @@ func (s Server) ListRecipesMemories(ctx echo.Context) error {
 	return ctx.JSON(http.StatusOK, memories)
 }
 
+func (s Server) ListRecipesEmbeddingModels(ctx echo.Context) error {
+	models, err := s.Services.RecipeService.EmbeddingModels(ctx.Request().Context(), MustGetUser(ctx).Subject)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, models)
+}
+
 func (s Server) CreateRecipe(ctx echo.Context, memory string) error {
 	identity := MustGetUser(ctx)
 	if !identity.CanWrite() {
@@ -23,6 +23,18 @@ import (
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
 
+	// (GET /jobs/{id})
+	GetJob(ctx echo.Context, id openapi_types.UUID) error
+
+	// (GET /kb/_chunks)
+	GetKbChunks(ctx echo.Context, params GetKbChunksParams) error
+
+	// (GET /kb/_chunks/{id})
+	GetKbChunk(ctx echo.Context, id openapi_types.UUID) error
+
+	// (GET /kb/_embedding_models)
+	ListKbEmbeddingModels(ctx echo.Context) error
+
 	// (GET /kb/_memories)
 	ListKbMemories(ctx echo.Context) error
 
@@ -35,9 +47,18 @@ type ServerInterface interface {
 	// (DELETE /kb/{memory}/documents/{document})
 	DeleteDocument(ctx echo.Context, memory string, document string) error
 
+	// (GET /kb/{memory}/documents/{document})
+	GetDocument(ctx echo.Context, memory string, document string, params GetDocumentParams) error
+
 	// (POST /kb/{memory}/documents/{document})
 	SubmitDocument(ctx echo.Context, memory string, document string) error
 
+	// (GET /kb/{memory}/settings)
+	GetKbSettings(ctx echo.Context, memory string) error
+
+	// (PUT /kb/{memory}/settings)
+	UpdateKbSettings(ctx echo.Context, memory string) error
+
 	// (GET /objects/_memories)
 	ListObjectsMemories(ctx echo.Context) error
 
@@ -53,6 +74,9 @@ type ServerInterface interface {
 	// (GET /objects/{memory}/_by-name)
 	GetObjectByName(ctx echo.Context, memory string, params GetObjectByNameParams) error
 
+	// (GET /recipes/_embedding_models)
+	ListRecipesEmbeddingModels(ctx echo.Context) error
+
 	// (GET /recipes/_memories)
 	ListRecipesMemories(ctx echo.Context) error
 
@@ -74,6 +98,73 @@ type ServerInterfaceWrapper struct {
 	Handler ServerInterface
 }
 
+// GetJob converts echo context to params.
+func (w *ServerInterfaceWrapper) GetJob(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetJob(ctx, id)
+	return err
+}
+
+// GetKbChunks converts echo context to params.
+func (w *ServerInterfaceWrapper) GetKbChunks(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetKbChunksParams
+	// ------------- Required query parameter "id" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "id", ctx.QueryParams(), &params.Id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetKbChunks(ctx, params)
+	return err
+}
+
+// GetKbChunk converts echo context to params.
+func (w *ServerInterfaceWrapper) GetKbChunk(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetKbChunk(ctx, id)
+	return err
+}
+
+// ListKbEmbeddingModels converts echo context to params.
+func (w *ServerInterfaceWrapper) ListKbEmbeddingModels(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ListKbEmbeddingModels(ctx)
+	return err
+}
+
 // ListKbMemories converts echo context to params.
 func (w *ServerInterfaceWrapper) ListKbMemories(ctx echo.Context) error {
 	var err error
@@ -114,6 +205,55 @@ func (w *ServerInterfaceWrapper) SearchKb(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter q: %s", err))
 	}
 
+	// ------------- Optional query parameter "mode" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "mode", ctx.QueryParams(), &params.Mode)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter mode: %s", err))
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// ------------- Optional query parameter "offset" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "offset", ctx.QueryParams(), &params.Offset)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter offset: %s", err))
+	}
+
+	// ------------- Optional query parameter "max_distance" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "max_distance", ctx.QueryParams(), &params.MaxDistance)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter max_distance: %s", err))
+	}
+
+	// ------------- Optional query parameter "mmr_lambda" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "mmr_lambda", ctx.QueryParams(), &params.MmrLambda)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter mmr_lambda: %s", err))
+	}
+
+	// ------------- Optional query parameter "max_per_document" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "max_per_document", ctx.QueryParams(), &params.MaxPerDocument)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter max_per_document: %s", err))
+	}
+
+	// ------------- Optional query parameter "context_window" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "context_window", ctx.QueryParams(), &params.ContextWindow)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter context_window: %s", err))
+	}
+
 	// Invoke the callback with all the unmarshaled arguments
 	err = w.Handler.SearchKb(ctx, memory, params)
 	return err
@@ -163,6 +303,69 @@ func (w *ServerInterfaceWrapper) DeleteDocument(ctx echo.Context) error {
 	return err
 }
 
+// GetDocument converts echo context to params.
+func (w *ServerInterfaceWrapper) GetDocument(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "memory" -------------
+	var memory string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "memory", ctx.Param("memory"), &memory, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter memory: %s", err))
+	}
+
+	// ------------- Path parameter "document" -------------
+	var document string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "document", ctx.Param("document"), &document, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter document: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetDocumentParams
+	// ------------- Optional query parameter "start_byte" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "start_byte", ctx.QueryParams(), &params.StartByte)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter start_byte: %s", err))
+	}
+
+	// ------------- Optional query parameter "end_byte" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "end_byte", ctx.QueryParams(), &params.EndByte)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter end_byte: %s", err))
+	}
+
+	// ------------- Optional query parameter "start_line" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "start_line", ctx.QueryParams(), &params.StartLine)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter start_line: %s", err))
+	}
+
+	// ------------- Optional query parameter "end_line" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "end_line", ctx.QueryParams(), &params.EndLine)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter end_line: %s", err))
+	}
+
+	// ------------- Optional query parameter "original" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "original", ctx.QueryParams(), &params.Original)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter original: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetDocument(ctx, memory, document, params)
+	return err
+}
+
 // SubmitDocument converts echo context to params.
 func (w *ServerInterfaceWrapper) SubmitDocument(ctx echo.Context) error {
 	var err error
@@ -189,6 +392,42 @@ func (w *ServerInterfaceWrapper) SubmitDocument(ctx echo.Context) error {
 	return err
 }
 
+// GetKbSettings converts echo context to params.
+func (w *ServerInterfaceWrapper) GetKbSettings(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "memory" -------------
+	var memory string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "memory", ctx.Param("memory"), &memory, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter memory: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetKbSettings(ctx, memory)
+	return err
+}
+
+// UpdateKbSettings converts echo context to params.
+func (w *ServerInterfaceWrapper) UpdateKbSettings(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "memory" -------------
+	var memory string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "memory", ctx.Param("memory"), &memory, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter memory: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.UpdateKbSettings(ctx, memory)
+	return err
+}
+
 // ListObjectsMemories converts echo context to params.
 func (w *ServerInterfaceWrapper) ListObjectsMemories(ctx echo.Context) error {
 	var err error
@@ -290,6 +529,17 @@ func (w *ServerInterfaceWrapper) GetObjectByName(ctx echo.Context) error {
 	return err
 }
 
+// ListRecipesEmbeddingModels converts echo context to params.
+func (w *ServerInterfaceWrapper) ListRecipesEmbeddingModels(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ListRecipesEmbeddingModels(ctx)
+	return err
+}
+
 // ListRecipesMemories converts echo context to params.
 func (w *ServerInterfaceWrapper) ListRecipesMemories(ctx echo.Context) error {
 	var err error
@@ -330,6 +580,27 @@ func (w *ServerInterfaceWrapper) SearchRecipes(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter q: %s", err))
 	}
 
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// ------------- Optional query parameter "offset" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "offset", ctx.QueryParams(), &params.Offset)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter offset: %s", err))
+	}
+
+	// ------------- Optional query parameter "max_distance" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "max_distance", ctx.QueryParams(), &params.MaxDistance)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter max_distance: %s", err))
+	}
+
 	// Invoke the callback with all the unmarshaled arguments
 	err = w.Handler.SearchRecipes(ctx, memory, params)
 	return err
@@ -433,16 +704,24 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 		Handler: si,
 	}
 
+	router.GET(baseURL+"/jobs/:id", wrapper.GetJob)
+	router.GET(baseURL+"/kb/_chunks", wrapper.GetKbChunks)
+	router.GET(baseURL+"/kb/_chunks/:id", wrapper.GetKbChunk)
+	router.GET(baseURL+"/kb/_embedding_models", wrapper.ListKbEmbeddingModels)
 	router.GET(baseURL+"/kb/_memories", wrapper.ListKbMemories)
 	router.GET(baseURL+"/kb/:memory", wrapper.SearchKb)
 	router.GET(baseURL+"/kb/:memory/documents", wrapper.ListDocuments)
 	router.DELETE(baseURL+"/kb/:memory/documents/:document", wrapper.DeleteDocument)
+	router.GET(baseURL+"/kb/:memory/documents/:document", wrapper.GetDocument)
 	router.POST(baseURL+"/kb/:memory/documents/:document", wrapper.SubmitDocument)
+	router.GET(baseURL+"/kb/:memory/settings", wrapper.GetKbSettings)
+	router.PUT(baseURL+"/kb/:memory/settings", wrapper.UpdateKbSettings)
 	router.GET(baseURL+"/objects/_memories", wrapper.ListObjectsMemories)
 	router.GET(baseURL+"/objects/:memory", wrapper.ListObjects)
 	router.POST(baseURL+"/objects/:memory", wrapper.CreateObject)
 	router.DELETE(baseURL+"/objects/:memory/_by-name", wrapper.DeleteObjectByName)
 	router.GET(baseURL+"/objects/:memory/_by-name", wrapper.GetObjectByName)
+	router.GET(baseURL+"/recipes/_embedding_models", wrapper.ListRecipesEmbeddingModels)
 	router.GET(baseURL+"/recipes/_memories", wrapper.ListRecipesMemories)
 	router.GET(baseURL+"/recipes/:memory", wrapper.SearchRecipes)
 	router.POST(baseURL+"/recipes/:memory", wrapper.CreateRecipe)
@@ -454,27 +733,79 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/8xZ32/bNhD+Vwhuj2rldHvSW7tgQ9Z1KdYNewgCgxLPNmuJVEjKiWHofx9IivphSbac",
-	"2Mleilgkj999d9+dTt3hRGS54MC1wtEOq2QFGbF/UqLJbfwdEm1+5VLkIDUDu5YIroHbBb3NAUdYacn4",
-	"EpeBX5u7hR2msCBFqnGENTzpME8J4zjAwIsMR3fdhyTPU5YQzQQPvythHtn1jMg1FY8c3wf9CznJYABJ",
-	"GWAJDwWTQM09dleNrmVIOB/LAFORFFnl1nR/NVnaPUxDpoZ3uAdESrLt4bLHD+Nac/GYAl3CPFkVfD0A",
-	"zz/uXd126dzIa9tB7YTFMcGF7rU/SljgCP8QNrkYVokY7vveAxXgDDIhKyYIpcxkD0m/dhg6dIU979yr",
-	"/uwRTDaEpSROYf5CzvYMDVElIWFOOSRNbxc4ujsM3+2fm1tAGQv74Bk1/y6EzIhRYVEwioMjamEU35fj",
-	"6OrbTlIKBZVIlpv4DK6PCPlcEqsqQBvFYd05X6fnahW5ITgKkkIyvf1mtjqTMRAJ8mOhV82vX32Qfv/3",
-	"bxy4amwMudUmaiutc1waw4wvhGWE6dSsfAFN0G0O/OPXGxzgDUhl6cabKwNM5MBJznCEf3o/e/8BBzgn",
-	"emXxhOs4nLe1tATt6ncrajhlSitE0hTpFaBanSgmCpCTD1Kp0CpAhFOziUlUZz2qQmEyxlb5G4oj/AdT",
-	"+nP8xV9tiFe54MrB+DCb7SVWr0vUjWuS1M0dlruuZ34NEQlIgi4kB2qD59LvDq9jkyZP7yBZCQWJVVXB",
-	"k+p8QvhcAqHOtmFz5/goR8lUQGSyAtXi0ZVHxDgibTp7nH2zRz/HNoKSZKBBKlsrmLFsoupTPvJlra0K",
-	"LQsIWqz1ykFl6KEAe7KypMkSt49NV+SwvYeTQN1fMDV6HWogRXphOnuqhL6pTlVgvf94yhiZXdfmL5Q3",
-	"Lw3R5ITqBadh4oJRCXf+z9JFJgUN/Ri550N10uubAtdswYCieIsIUjkkbMEStGApoKpTdcN3bW1eNy9d",
-	"ezz/3IfRXMsUcphOJeRRMg32zEXLTNcQbTt5QublQg1V2SLOmEkLxOGxFgzS4liJteeuu1hA6U+Cbs9W",
-	"dmpXy9K9tvS1cyCmJKnYeV5QTZq71x012Pr79cMNhOpcvfr5aj/erSvHTlC8p2Kgb48ycTYGDiZJM4pP",
-	"oKZy41zMXK5ReLl2yf1FAtFQuXsh0bX4HJLdVV92jjOjucTiexajQ7qre8w83r7zE1DTV4Z6gIP+afun",
-	"axPH+0ADfqgJnAg+GNbEb6AP45pdKHzBqLP/3+QfeRvmnrjJIjKZVI2pJw9v1bmXTG1/ORNvP7ppYkGO",
-	"1zw/y0/vBp7W6VOcJ3Ti8FaRN/Im/gqD19sMWj4SY3E8Uwgv37a62F1b8G+ZDuvxTHDNzmXChZrd/vfB",
-	"0YZ3xttGgzvSPyeGtt0/9+UZ7tyTGzptMmtGLunZH+q1rdAc67LewaEee6KDrzpted4Omjr2+XhUF0VO",
-	"yTTC/7E731wLs1fUgiPnZVpofV22qdL+rnxnP+IrkBufSIVMcYRDkrNwc4XtJ353766bDsr+R0L1aB23",
-	"f/k3qPK+/C8AAP//5u1rSz8cAAA=",
+	"H4sIAAAAAAAC/+xbW3PctpL+KyjuPtIjySc5D3pL4uTEuRynYmd3q1yuGZDoGcIiAQYAJc1RzX/falxI",
+	"kARHo5uzW7svKg0JAo0PffnQaNxlpWxaKUAYnV3eZbqsoKH237LqxNW6lMLArcEHDHSpeGu4FNllZiog",
+	"tglpQO2AkRtuKsKNJgL4ripkp3RObioQhBLfC7nhgskbwjVR8GcH2gDL8qxVsgVlOAzj4j9m30J2mWmj",
+	"uNhlhzwDwdZSMS5oHb3nwsAOFDbQhipzrMkhz3BgroBllx/9UNPvxgN9ykMvsvgMpcFx7Hdc7NYajOFi",
+	"p+fwVPKGMFl2DSJLqAKi25obwoWRDje9In8IDYZsOdRMky2ta1LQ8ooYSRDd0DmRW/u7gUaqPdG1NDk+",
+	"EENDdQ2KMNjSrjZ6hmihgF61kguzbkGVIAyvYS6yhoYKw0sSpkfKzmhcQgV2HMa1oaIEUoC5ARDDSmNj",
+	"DcKAKEHj8tJCXuNHXJNhSJwITlJ3ZdV3plfkR76rQJFrWnegyY5fA9nCDaic1FTtQHm8sjzbStVQk11m",
+	"THZFDVmeNfSWN12TXV6cn+dZw4X7dd6vmuiawmkHiFIyVKWkLht5BYL/CxRpgGo3JftMW4XATj9mZX1x",
+	"fn61LqjGsduvh/9V//+nfK658hpUTdu0mjQIh19jEAz/pd60rKG0QA0wQo1bbNTV0FzArckJF/ZHJ7hZ",
+	"kfejVdSESSKkIUGCJEaxCfF/QRogjzTBFpGMo+Hj7i+S3RtFDez28yE2tDNy46xEk4aqKyZvRGRDxZ5U",
+	"QHEBCRWMwDWovanwJ9QaiIKyU5pfQ73Pyab/tcHvWqroTtG20jmpuQBte7iRimnSoWoGsLbc5GQTBt9E",
+	"Y+ZkExR8Y7/e9J1uSItWe1PJGiIrkGo07iaY18aZVbCh4QNnaNAUwOwsNW94TRU3e8KUbLGPUjI3o20n",
+	"SgQuJ4ixFaisqdboBLjg+EqviMe05eWVDh9vpSJadqoEsuU1xNqNrVGVA3bWvBwUWZ4FQVHxw7zsYzet",
+	"LM9wgKT6W9VI6r5VJpTe66f1lM4AgUWilRVVtDSgUF5vlvORDglfzaih79yvy7tpqJE4JZMMNv7d2r24",
+	"y7x3zS4zjGRnbU25iAQcPaRtW/OS4jzPPmuJj+z7Hs0USII2kJBkErJsq166ZHQKJpOYr3fs+P+/K9hm",
+	"l9m/nQ0E4MxH/7N5fBsASYJlqIuB3ECj0y3cA6oU3c8mZT8/bVLrSIzFxUx4d7jtnWboyjIW77wUFTvI",
+	"EqsSg3kMsishb2pgO1j3X3jGUuzNgkfFN3Z0LzoBwdAxbKWCLOU9sTv0X2n2g+a8Dlo0Hw3fBAiw6QQL",
+	"qknX1pIyDDU6BUUDjNPIHmYBAt87f3TqMDlhYKA0wMhWycbRR9o4lxAWNCGKI2wB2SUiuAyVkYbW9nud",
+	"nksIcvi/c+yWBeeLfeFY+gTK2WvHoO6j6UQ6M5pGtPhj8ccCHDUdB/xxtzCG4qf37/5JLG8CNtDC4BVy",
+	"67UVt9EKkZICUmzVxhzLBaPZz9Z060lpz/EKLqjap5o+0d3YkVJI9bF33UgG9brTdAcpT9MJMxKVC/P3",
+	"r5LaUXZKJb3STQWmAo8L18EPbPkOQx+xAuQe1XqP0BINVJUVaIL+BwMl3VEudIRmIWUNVFhrxQ6S2LRK",
+	"XnMG6v5g07cM3eV+7sO8ToDRYkYZs6yE1r+NsOyX8JhzTa9Lapm52IHGcdafZTFfOWoMNK3Raa/gSODa",
+	"jQbsaCMjXbsjG2M9CzimooYIADawvBX5zrW9qaQeIgHjzNL2ssLAZFkR/gyiuZVPK5yy24U1HWsoowZe",
+	"Gd7cG+QWX66vQWk7xdSM/cvZlKUoLTeXillCNxcYlJIq3al9FbqsqTZkS3mN03frmHYjguvqgQBwNmrb",
+	"dZylIyD6tCRK1lU/cFRtaIoa0Mks0Tv82UEXlj0nnahBa8INKalAvdBdWQIw3HMIGb5zaYcatibiqa6j",
+	"LM9UJwTKkWf+Y/vUjZym1rFnsPh4OPI4qLk55YOlzS1mbmgjrU15lIFd9dmhU5NGUQLrXsrbZ7tQ7X1+",
+	"YsHApeYiyoj4TMyfHaj9inxTaBCGcEGuYI/bzN51J3MYs0zFUXuMSeBcMGsl+DpOI8ht2PJhJEe5uEkF",
+	"4wnVlNutBnOEu7oGUYbIDWdprCfWgXjHA+YEbsu6wy1mjsgVQBRQ5pJfVIdEyNqPQWj/5ZSnRwL7ffoC",
+	"nwtvIykx34bbfwYqd+wT38nOgGqkDaqn0ovgP8bDcgbC8C2HaFA73ZIbQNtFjrsFU1b2B1r2irw1RBu6",
+	"d59oy4Q1qSXmPDSZbmTcPJgEnRNaKqk10V3RcI2eWE9Rz/InObgoqzqHt5XaRvexZBM9y13uCsmihfu8",
+	"NxTkhz5khlDht0F9z5rcWCVTtLxaCCRj0j8XcsvVg21jKvPFkZEfay7263sMZsFKho3DyXai900hF5Zx",
+	"klWSyieVFrCyuSbQL8DQF8NLyBSgOKdEitM55uTDlJUn9vjpQLTudwgL/PGJOZh1RXWVXsL3P37z6vXX",
+	"fw+GCLfG5s2Y1asV+b5pzd7a25BYnZocd9v0malFMfURHPPpFGshQXYsZx1v48dQ2LR12EGfsI97qELn",
+	"WdeyB4O0SLF3St5ol/Mm+PGYZHPv+I0J53Bu36ATUzlqaT67OM7HOW3zMOcjDR8kjhR7pB8jHFIGa8fm",
+	"cHSTeMxOvOyHke5Mdn3XlNe0qGH9RLc06WhxOvvRweCz5WBT6W0FJXcJOVrX77bZ5cfj3br2a3/8mh3y",
+	"qXyP573WpBzRJQq0P4o8ge6e5Brmevvp8GkRkH6CD8r5j+b7EA/0PPlvb32xFMeT4m6up8c41z4pjsZD",
+	"H27277GpPzYGqkB905lq+PVDWKSf/vNDlrtyAZt4sm+HVauMabODy8hspUWEmxrf/AqGknctiG9+exv5",
+	"j8vs+sLSzBYEbXl2mf1tdb56bQ+bTGXlOfssC312x9kBf+1SVEtBK5XpuZFxieRWyZ0CrR1H+iyLyFsW",
+	"e9u4pHVt5UdVsQc3b1l2mf0DzE+ysGdiupVCO2Ben59PNGl24NOXUty3JOOElUVsbnUosj0KNp0SwBCn",
+	"r86/SlsotmUStMsX3XJtciRyGNFb1QlghG4NKJtDrMF+eOg1+GOGGKOu3b6CspIaSmuanhdml1lJxRpp",
+	"qP0IzwEbsKdy6Hc4NsDlCrp86cLLoOpGdZBHyNxn858OeXZ2VZxFZG5h3REZTXqGFrYTxZ68fdMfUSPN",
+	"6fNJb9/oFf7B3761zc8xOYFOSLsDA+RMuSvluOJtCyylLj8X34VihRQ61lGeDE9v1PfSpok9f3pBjZ0x",
+	"7AWldW8tXmPFPU8orpSkoWJv18N94cuDCHU5xJGSXhUnquhYfe7zHU6H6FSLnBIdWezsy6F9FOyTfcSQ",
+	"OBhUvU9EO/jbmpZIJm2ZUQVcuY1p4JWPWo0v6TBSZxDJZbdEVuektZU/4wqruT/pE/DFngAtq6hcI5yQ",
+	"jLXkF67Nz8X3odWvTpgXVJjZxBMaMxF6aqRPsbSYzifxrrk22paBjQEuqB4dGurcxm6nez3xJp4qpUD+",
+	"NQz9guj200ugGt49H5p3Do9ln9WfCM4UlQtCYzhnmL23n/5cZKdYZb9BXLbMGV1PRzxDd1kyxN3LmNP9",
+	"/flQoeY1SFGgaqgpK2ArsrmG0khla636usihICsnG5/Z99VYdf3K+kfbATIKt1XHFoFv+D3SptoXirvv",
+	"CmlBTs0LDXME1FB/5ESLznT6B16mLM/cIMlznGO1fW5PhhL7DdyK/IerygwFnX21aemLtWqJiVkWdoIl",
+	"bRdmVPOGm9GUjpUKpuWcyYfDIhuzGdKW7rhwx08pAXx+NinB+akSTDfABewl1hJWfNj1WlwY1yVVDNis",
+	"SiI65TeVAl3Jmi1pAb1dh6GyZBycF8K+PloGO58V43YP1h9UhEkUe6cWtCYNVYhsTRTUcI3CrMjmYkMU",
+	"9Ry7f26LFXKnFKGgd0uvZdcn+DtR8ysYykVojT5v3zvMFfm974xrbEMaLjo9rkCWW+K03gYJp+1B8j5w",
+	"DMcSQ5WXLWCR2/5gDpsqwBfAoqxFcjEata5pUzB66lJcPHAp0qY4Liawh0Ih87cim/MNaYAKTYQ8Ynyo",
+	"SC2odXxS+wQrGIQblYA7SXMihSNHmjPIR/cD7GMHsyuF50aH2wELovu3a3d34HTB/+o90CwkPzstOAur",
+	"eSrb6tvfTw+QUr3pu38hjvDUJXrgwU5cmTlhF7PVG9/dCMuWo7Oz833e9Tu7C/8e3BrWkKoMcc9T7Dmw",
+	"vnDmbDcolOgWSr7lpavE9PnF8UK/sX2+GfzCZEUSW8hhWK6Jk+mhCn2juHEg3rMXHxXrDgek/ozKVjuM",
+	"jz5yX68pFSp5S5VL4vTlqDxV6zrb2Ud4TDR/6Vw5HML2Ihrp1cZXF5wv+LdR8edTnHJfTjwePq4qXpFv",
+	"ZSf8ZSP02L4EmpK+wN7q+w0qkiN1XJR1xxC3BfnjetUnSD8+nV9E8WJFfrE3OHzlUwGYySy4CBEmnOkt",
+	"Q+2LaJ/CQ0dVNnNZjyD1xMGDYZxYXY1GYLSTzqZyBluhmmB174r8bjM6Ie2JDne/RKAV3/kbcjOf3lee",
+	"vmjcnd0CQA8SdydLA+aVNgpoM+723rLidGItPmO9P40ZbhJga9nZOo7CGtzRdFw/xkLefnpAH18ZkNso",
+	"qmPjK2jNy6XnnpAIGHfE4oDzAL7QSp3Kg1i1xnghYLh3iQZJx0k94LbomupJOQBCTfF7NKoV+RAV4vz2",
+	"5oecvHn33X/l5McPv/6Sk+9/++PbnPz+4Yd8uKZmj5nw9o/7DHuxVd3DKFJ405xnYqzwb8aAgDbfSrZ/",
+	"dstxB+VdbTiGxjO0ileMGvoIG/TTORwOh5nJv/6yB2SxlfpS2VAx5U3H7wCxeGqn0CSx1k4PR4PupqVU",
+	"0ZngZ1msiFscWwAWkRFbrTdQErsnCxZpr65Mr0qQGug12JJdav9ynZOic6Vv1kW7vGYFlFnzu8t+kQ6v",
+	"hYo7avpLo5/tEeWy2RyO+au5qFRBcF7WmVnfdfG3paK62no7I6W7retaf32kNYpHuHN0umsd6Paz16+P",
+	"D9LQGhXWFTpj2gQ7Ia5i91H0c0rI47KNo8R0ej975GbCxe45tIk73tOr24lDpvdBqpdObO/jYpOZoY0U",
+	"5Gkb2ZfbT7ZdctXscda9y/ahgr1jQGF9htgaMSc8P7+his2X6w9b6zRZsed35onFOpyycRutoK/LOkpm",
+	"jviER9ubK1/RyaOieQ7CXd3Vz3W2c/JxwyNOd/zEHpALCFAkznkWkci+RM4kujR9AjR+Gs+FzAs6B6kT",
+	"4H5nqxX9dF+IfUV4pmz1Ym5+DjOMer6a8jGIpuyuD3brYv9quC4cMk6p7JAT/dv9P10C6X5HMwifSg89",
+	"UPg8bRP/AHNcrvMXWr58cbL/c5V/4fRUBOBONiLUJF92+FxFFr67x9VW/O4+/l9bYOEn/4Cg0aP/wFKL",
+	"APMTaiw82H99oYWhVsiXgPX0mosA6ImlFh680woDX6JM4gFlEf9fE/B/qibgJVO2wRKX7PiZTPjlSeMk",
+	"ollSFjKOTtb7PYGjms4TvBDVnF7wWKSbzzja4uIusNcTlzZmr1P3fHbnnrxlp52YDkehKqCfYrrR0tzH",
+	"ccMEUwz3gRP8opn3gNvT6myX7MJlFU4B3OVK/nJbOP+CttCnXJ5gC9FdHasq8S2dj/ZKlAvGTpE6VWeX",
+	"2Rlt+Rner/nUj3s3Vgdtb4L5R1dF/CvsX6JH9pLI4dPhvwcA17OfKj5UAAA=",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
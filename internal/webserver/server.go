@@ -70,10 +70,11 @@ func NewServer() (*Server, error) {
 	}, true)
 	grp.Use(echosec.WithOpenApiConfig(cfg))
 	server.E.HTTPErrorHandler = func(err error, c echo.Context) {
-		_ = c.JSON(500, map[string]string{"error": err.Error()})
+		_ = c.JSON(errorStatus(err), map[string]string{"error": err.Error()})
 	}
 	RegisterHandlers(grp, &server)
 	server.initMCP()
+	server.initHealth()
 	return &server, nil
 }
 
@@ -18,12 +18,47 @@
 package webserver
 
 import (
+	"context"
+	"errors"
+	"net/http"
+
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/labstack/echo/v4"
 	"github.com/theirish81/meta/internal/auth"
+	"github.com/theirish81/meta/internal/extraction"
+	"github.com/theirish81/meta/internal/persistence/services"
+	"gorm.io/gorm"
 )
 
 func MustGetUser(ctx echo.Context) *auth.MetaClaims {
 	token := ctx.Get("user").(*jwt.Token)
 	return token.Claims.(*auth.MetaClaims)
 }
+
+// errorStatus maps an error to the HTTP status code that best describes it
+func errorStatus(err error) int {
+	var httpError *echo.HTTPError
+	switch {
+	case errors.As(err, &httpError):
+		return httpError.Code
+	case errors.Is(err, services.ErrInvalidSearch), errors.Is(err, services.ErrInvalidChunking),
+		errors.Is(err, services.ErrInvalidRange), errors.Is(err, services.ErrTooManyChunks):
+		return http.StatusBadRequest
+	case errors.Is(err, extraction.ErrUnsupportedFormat):
+		return http.StatusUnsupportedMediaType
+	case errors.Is(err, extraction.ErrUnreadableDocument):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, gorm.ErrRecordNotFound), errors.Is(err, services.ErrDocumentNotFound),
+		errors.Is(err, services.ErrChunkNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	case errors.Is(err, services.ErrProviderUnavailable):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, services.ErrModelNotFound), errors.Is(err, services.ErrDimensionMismatch),
+		errors.Is(err, services.ErrInvalidEmbedding):
+		return http.StatusBadGateway
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -18,6 +18,7 @@
 package main
 
 import (
+	"context"
 	"os"
 
 	"github.com/theirish81/meta/internal/cmd"
@@ -37,6 +38,7 @@ func main() {
 	if err := services.Init(); err != nil {
 		panic(err)
 	}
+	services.Services.JobService.Start(context.Background(), config.Instance.JobWorkers)
 	srv, err := webserver.NewServer()
 	if err != nil {
 		panic(err)
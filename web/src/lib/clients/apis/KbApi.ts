@@ -16,16 +16,31 @@
 import * as runtime from '../runtime';
 import type {
   Document,
+  DocumentContent,
+  EmbeddingModelUsage,
+  IngestionJob,
   KnowledgeChunk,
+  KnowledgeDocument,
   Memory,
+  MemorySettings,
 } from '../models/index';
 import {
     DocumentFromJSON,
     DocumentToJSON,
+    DocumentContentFromJSON,
+    DocumentContentToJSON,
+    EmbeddingModelUsageFromJSON,
+    EmbeddingModelUsageToJSON,
+    IngestionJobFromJSON,
+    IngestionJobToJSON,
     KnowledgeChunkFromJSON,
     KnowledgeChunkToJSON,
+    KnowledgeDocumentFromJSON,
+    KnowledgeDocumentToJSON,
     MemoryFromJSON,
     MemoryToJSON,
+    MemorySettingsFromJSON,
+    MemorySettingsToJSON,
 } from '../models/index';
 
 export interface DeleteDocumentRequest {
@@ -33,6 +48,28 @@ export interface DeleteDocumentRequest {
     document: string;
 }
 
+export interface GetDocumentRequest {
+    memory: string;
+    document: string;
+    startByte?: number;
+    endByte?: number;
+    startLine?: number;
+    endLine?: number;
+    original?: boolean;
+}
+
+export interface GetKbChunkRequest {
+    id: string;
+}
+
+export interface GetKbChunksRequest {
+    id: Array<string>;
+}
+
+export interface GetKbSettingsRequest {
+    memory: string;
+}
+
 export interface ListDocumentsRequest {
     memory: string;
 }
@@ -41,6 +78,13 @@ export interface SearchKbRequest {
     memory: string;
     q: string;
     tag?: Array<string>;
+    mode?: SearchKbModeEnum;
+    limit?: number;
+    offset?: number;
+    maxDistance?: number;
+    mmrLambda?: number;
+    maxPerDocument?: number;
+    contextWindow?: number;
 }
 
 export interface SubmitDocumentRequest {
@@ -49,6 +93,11 @@ export interface SubmitDocumentRequest {
     document2?: Document;
 }
 
+export interface UpdateKbSettingsRequest {
+    memory: string;
+    memorySettings?: MemorySettings;
+}
+
 /**
  * 
  */
@@ -106,10 +155,221 @@ export class KbApi extends runtime.BaseAPI {
         await this.deleteDocumentRaw(requestParameters, initOverrides);
     }
 
+    /**
+     * returns a document with the text it was last submitted with, whole or in part, or the file it was uploaded as
+     */
+    async getDocumentRaw(requestParameters: GetDocumentRequest, initOverrides?: RequestInit | runtime.InitOverrideFunction): Promise<runtime.ApiResponse<DocumentContent>> {
+        if (requestParameters['memory'] == null) {
+            throw new runtime.RequiredError(
+                'memory',
+                'Required parameter "memory" was null or undefined when calling getDocument().'
+            );
+        }
+
+        if (requestParameters['document'] == null) {
+            throw new runtime.RequiredError(
+                'document',
+                'Required parameter "document" was null or undefined when calling getDocument().'
+            );
+        }
+
+        const queryParameters: any = {};
+
+        if (requestParameters['startByte'] != null) {
+            queryParameters['start_byte'] = requestParameters['startByte'];
+        }
+
+        if (requestParameters['endByte'] != null) {
+            queryParameters['end_byte'] = requestParameters['endByte'];
+        }
+
+        if (requestParameters['startLine'] != null) {
+            queryParameters['start_line'] = requestParameters['startLine'];
+        }
+
+        if (requestParameters['endLine'] != null) {
+            queryParameters['end_line'] = requestParameters['endLine'];
+        }
+
+        if (requestParameters['original'] != null) {
+            queryParameters['original'] = requestParameters['original'];
+        }
+
+        const headerParameters: runtime.HTTPHeaders = {};
+
+        if (this.configuration && this.configuration.accessToken) {
+            const token = this.configuration.accessToken;
+            const tokenString = await token("bearerAuth", []);
+
+            if (tokenString) {
+                headerParameters["Authorization"] = `Bearer ${tokenString}`;
+            }
+        }
+
+        let urlPath = `/kb/{memory}/documents/{document}`;
+        urlPath = urlPath.replace(`{${"memory"}}`, encodeURIComponent(String(requestParameters['memory'])));
+        urlPath = urlPath.replace(`{${"document"}}`, encodeURIComponent(String(requestParameters['document'])));
+
+        const response = await this.request({
+            path: urlPath,
+            method: 'GET',
+            headers: headerParameters,
+            query: queryParameters,
+        }, initOverrides);
+
+        return new runtime.JSONApiResponse(response, (jsonValue) => DocumentContentFromJSON(jsonValue));
+    }
+
+    /**
+     * returns a document with the text it was last submitted with, whole or in part, or the file it was uploaded as
+     */
+    async getDocument(requestParameters: GetDocumentRequest, initOverrides?: RequestInit | runtime.InitOverrideFunction): Promise<DocumentContent> {
+        const response = await this.getDocumentRaw(requestParameters, initOverrides);
+        return await response.value();
+    }
+
+    /**
+     * returns a knowledge chunk by ID
+     */
+    async getKbChunkRaw(requestParameters: GetKbChunkRequest, initOverrides?: RequestInit | runtime.InitOverrideFunction): Promise<runtime.ApiResponse<KnowledgeChunk>> {
+        if (requestParameters['id'] == null) {
+            throw new runtime.RequiredError(
+                'id',
+                'Required parameter "id" was null or undefined when calling getKbChunk().'
+            );
+        }
+
+        const queryParameters: any = {};
+
+        const headerParameters: runtime.HTTPHeaders = {};
+
+        if (this.configuration && this.configuration.accessToken) {
+            const token = this.configuration.accessToken;
+            const tokenString = await token("bearerAuth", []);
+
+            if (tokenString) {
+                headerParameters["Authorization"] = `Bearer ${tokenString}`;
+            }
+        }
+
+        let urlPath = `/kb/_chunks/{id}`;
+        urlPath = urlPath.replace(`{${"id"}}`, encodeURIComponent(String(requestParameters['id'])));
+
+        const response = await this.request({
+            path: urlPath,
+            method: 'GET',
+            headers: headerParameters,
+            query: queryParameters,
+        }, initOverrides);
+
+        return new runtime.JSONApiResponse(response, (jsonValue) => KnowledgeChunkFromJSON(jsonValue));
+    }
+
+    /**
+     * returns a knowledge chunk by ID
+     */
+    async getKbChunk(requestParameters: GetKbChunkRequest, initOverrides?: RequestInit | runtime.InitOverrideFunction): Promise<KnowledgeChunk> {
+        const response = await this.getKbChunkRaw(requestParameters, initOverrides);
+        return await response.value();
+    }
+
+    /**
+     * returns knowledge chunks by ID, in the order of the IDs. IDs of chunks that do not exist, or no longer do, are skipped
+     */
+    async getKbChunksRaw(requestParameters: GetKbChunksRequest, initOverrides?: RequestInit | runtime.InitOverrideFunction): Promise<runtime.ApiResponse<Array<KnowledgeChunk>>> {
+        if (requestParameters['id'] == null) {
+            throw new runtime.RequiredError(
+                'id',
+                'Required parameter "id" was null or undefined when calling getKbChunks().'
+            );
+        }
+
+        const queryParameters: any = {};
+
+        if (requestParameters['id'] != null) {
+            queryParameters['id'] = requestParameters['id'];
+        }
+
+        const headerParameters: runtime.HTTPHeaders = {};
+
+        if (this.configuration && this.configuration.accessToken) {
+            const token = this.configuration.accessToken;
+            const tokenString = await token("bearerAuth", []);
+
+            if (tokenString) {
+                headerParameters["Authorization"] = `Bearer ${tokenString}`;
+            }
+        }
+
+        let urlPath = `/kb/_chunks`;
+
+        const response = await this.request({
+            path: urlPath,
+            method: 'GET',
+            headers: headerParameters,
+            query: queryParameters,
+        }, initOverrides);
+
+        return new runtime.JSONApiResponse(response, (jsonValue) => jsonValue.map(KnowledgeChunkFromJSON));
+    }
+
+    /**
+     * returns knowledge chunks by ID, in the order of the IDs. IDs of chunks that do not exist, or no longer do, are skipped
+     */
+    async getKbChunks(requestParameters: GetKbChunksRequest, initOverrides?: RequestInit | runtime.InitOverrideFunction): Promise<Array<KnowledgeChunk>> {
+        const response = await this.getKbChunksRaw(requestParameters, initOverrides);
+        return await response.value();
+    }
+
+    /**
+     * returns the settings of a memory slot. Unset chunking settings fall back to the server defaults
+     */
+    async getKbSettingsRaw(requestParameters: GetKbSettingsRequest, initOverrides?: RequestInit | runtime.InitOverrideFunction): Promise<runtime.ApiResponse<MemorySettings>> {
+        if (requestParameters['memory'] == null) {
+            throw new runtime.RequiredError(
+                'memory',
+                'Required parameter "memory" was null or undefined when calling getKbSettings().'
+            );
+        }
+
+        const queryParameters: any = {};
+
+        const headerParameters: runtime.HTTPHeaders = {};
+
+        if (this.configuration && this.configuration.accessToken) {
+            const token = this.configuration.accessToken;
+            const tokenString = await token("bearerAuth", []);
+
+            if (tokenString) {
+                headerParameters["Authorization"] = `Bearer ${tokenString}`;
+            }
+        }
+
+        let urlPath = `/kb/{memory}/settings`;
+        urlPath = urlPath.replace(`{${"memory"}}`, encodeURIComponent(String(requestParameters['memory'])));
+
+        const response = await this.request({
+            path: urlPath,
+            method: 'GET',
+            headers: headerParameters,
+            query: queryParameters,
+        }, initOverrides);
+
+        return new runtime.JSONApiResponse(response, (jsonValue) => MemorySettingsFromJSON(jsonValue));
+    }
+
+    /**
+     * returns the settings of a memory slot. Unset chunking settings fall back to the server defaults
+     */
+    async getKbSettings(requestParameters: GetKbSettingsRequest, initOverrides?: RequestInit | runtime.InitOverrideFunction): Promise<MemorySettings> {
+        const response = await this.getKbSettingsRaw(requestParameters, initOverrides);
+        return await response.value();
+    }
+
     /**
      * lists all the documents in a memory slot
      */
-    async listDocumentsRaw(requestParameters: ListDocumentsRequest, initOverrides?: RequestInit | runtime.InitOverrideFunction): Promise<runtime.ApiResponse<Array<string>>> {
+    async listDocumentsRaw(requestParameters: ListDocumentsRequest, initOverrides?: RequestInit | runtime.InitOverrideFunction): Promise<runtime.ApiResponse<Array<KnowledgeDocument>>> {
         if (requestParameters['memory'] == null) {
             throw new runtime.RequiredError(
                 'memory',
@@ -140,17 +400,54 @@ export class KbApi extends runtime.BaseAPI {
             query: queryParameters,
         }, initOverrides);
 
-        return new runtime.JSONApiResponse<any>(response);
+        return new runtime.JSONApiResponse(response, (jsonValue) => jsonValue.map(KnowledgeDocumentFromJSON));
     }
 
     /**
      * lists all the documents in a memory slot
      */
-    async listDocuments(requestParameters: ListDocumentsRequest, initOverrides?: RequestInit | runtime.InitOverrideFunction): Promise<Array<string>> {
+    async listDocuments(requestParameters: ListDocumentsRequest, initOverrides?: RequestInit | runtime.InitOverrideFunction): Promise<Array<KnowledgeDocument>> {
         const response = await this.listDocumentsRaw(requestParameters, initOverrides);
         return await response.value();
     }
 
+    /**
+     * counts, per memory slot, the knowledge chunks embedded by each embedding model
+     */
+    async listKbEmbeddingModelsRaw(initOverrides?: RequestInit | runtime.InitOverrideFunction): Promise<runtime.ApiResponse<{ [key: string]: Array<EmbeddingModelUsage>; }>> {
+        const queryParameters: any = {};
+
+        const headerParameters: runtime.HTTPHeaders = {};
+
+        if (this.configuration && this.configuration.accessToken) {
+            const token = this.configuration.accessToken;
+            const tokenString = await token("bearerAuth", []);
+
+            if (tokenString) {
+                headerParameters["Authorization"] = `Bearer ${tokenString}`;
+            }
+        }
+
+        let urlPath = `/kb/_embedding_models`;
+
+        const response = await this.request({
+            path: urlPath,
+            method: 'GET',
+            headers: headerParameters,
+            query: queryParameters,
+        }, initOverrides);
+
+        return new runtime.JSONApiResponse<any>(response);
+    }
+
+    /**
+     * counts, per memory slot, the knowledge chunks embedded by each embedding model
+     */
+    async listKbEmbeddingModels(initOverrides?: RequestInit | runtime.InitOverrideFunction): Promise<{ [key: string]: Array<EmbeddingModelUsage>; }> {
+        const response = await this.listKbEmbeddingModelsRaw(initOverrides);
+        return await response.value();
+    }
+
     /**
      * lists all the knowledge base memory slots, and their available tags
      */
@@ -216,6 +513,34 @@ export class KbApi extends runtime.BaseAPI {
             queryParameters['q'] = requestParameters['q'];
         }
 
+        if (requestParameters['mode'] != null) {
+            queryParameters['mode'] = requestParameters['mode'];
+        }
+
+        if (requestParameters['limit'] != null) {
+            queryParameters['limit'] = requestParameters['limit'];
+        }
+
+        if (requestParameters['offset'] != null) {
+            queryParameters['offset'] = requestParameters['offset'];
+        }
+
+        if (requestParameters['maxDistance'] != null) {
+            queryParameters['max_distance'] = requestParameters['maxDistance'];
+        }
+
+        if (requestParameters['mmrLambda'] != null) {
+            queryParameters['mmr_lambda'] = requestParameters['mmrLambda'];
+        }
+
+        if (requestParameters['maxPerDocument'] != null) {
+            queryParameters['max_per_document'] = requestParameters['maxPerDocument'];
+        }
+
+        if (requestParameters['contextWindow'] != null) {
+            queryParameters['context_window'] = requestParameters['contextWindow'];
+        }
+
         const headerParameters: runtime.HTTPHeaders = {};
 
         if (this.configuration && this.configuration.accessToken) {
@@ -249,9 +574,9 @@ export class KbApi extends runtime.BaseAPI {
     }
 
     /**
-     * submits a new document to a memory slot
+     * submits a new document to a memory slot, either as extracted text or as a file. The text of PDF, DOCX, HTML, EPUB, RTF, markdown and plain text files is extracted on upload
      */
-    async submitDocumentRaw(requestParameters: SubmitDocumentRequest, initOverrides?: RequestInit | runtime.InitOverrideFunction): Promise<runtime.ApiResponse<void>> {
+    async submitDocumentRaw(requestParameters: SubmitDocumentRequest, initOverrides?: RequestInit | runtime.InitOverrideFunction): Promise<runtime.ApiResponse<IngestionJob>> {
         if (requestParameters['memory'] == null) {
             throw new runtime.RequiredError(
                 'memory',
@@ -293,14 +618,73 @@ export class KbApi extends runtime.BaseAPI {
             body: DocumentToJSON(requestParameters['document2']),
         }, initOverrides);
 
+        return new runtime.JSONApiResponse(response, (jsonValue) => IngestionJobFromJSON(jsonValue));
+    }
+
+    /**
+     * submits a new document to a memory slot, either as extracted text or as a file. The text of PDF, DOCX, HTML, EPUB, RTF, markdown and plain text files is extracted on upload
+     */
+    async submitDocument(requestParameters: SubmitDocumentRequest, initOverrides?: RequestInit | runtime.InitOverrideFunction): Promise<IngestionJob> {
+        const response = await this.submitDocumentRaw(requestParameters, initOverrides);
+        return await response.value();
+    }
+
+    /**
+     * replaces the settings of a memory slot. They apply to the documents submitted afterwards
+     */
+    async updateKbSettingsRaw(requestParameters: UpdateKbSettingsRequest, initOverrides?: RequestInit | runtime.InitOverrideFunction): Promise<runtime.ApiResponse<void>> {
+        if (requestParameters['memory'] == null) {
+            throw new runtime.RequiredError(
+                'memory',
+                'Required parameter "memory" was null or undefined when calling updateKbSettings().'
+            );
+        }
+
+        const queryParameters: any = {};
+
+        const headerParameters: runtime.HTTPHeaders = {};
+
+        headerParameters['Content-Type'] = 'application/json';
+
+        if (this.configuration && this.configuration.accessToken) {
+            const token = this.configuration.accessToken;
+            const tokenString = await token("bearerAuth", []);
+
+            if (tokenString) {
+                headerParameters["Authorization"] = `Bearer ${tokenString}`;
+            }
+        }
+
+        let urlPath = `/kb/{memory}/settings`;
+        urlPath = urlPath.replace(`{${"memory"}}`, encodeURIComponent(String(requestParameters['memory'])));
+
+        const response = await this.request({
+            path: urlPath,
+            method: 'PUT',
+            headers: headerParameters,
+            query: queryParameters,
+            body: MemorySettingsToJSON(requestParameters['memorySettings']),
+        }, initOverrides);
+
         return new runtime.VoidApiResponse(response);
     }
 
     /**
-     * submits a new document to a memory slot
+     * replaces the settings of a memory slot. They apply to the documents submitted afterwards
      */
-    async submitDocument(requestParameters: SubmitDocumentRequest, initOverrides?: RequestInit | runtime.InitOverrideFunction): Promise<void> {
-        await this.submitDocumentRaw(requestParameters, initOverrides);
+    async updateKbSettings(requestParameters: UpdateKbSettingsRequest, initOverrides?: RequestInit | runtime.InitOverrideFunction): Promise<void> {
+        await this.updateKbSettingsRaw(requestParameters, initOverrides);
     }
 
 }
+
+/**
+ * @export
+ */
+export const SearchKbModeEnum = {
+    Vector: 'vector',
+    Keyword: 'keyword',
+    Hybrid: 'hybrid'
+} as const;
+export type SearchKbModeEnum = typeof SearchKbModeEnum[keyof typeof SearchKbModeEnum];
+
@@ -15,11 +15,14 @@
 
 import * as runtime from '../runtime';
 import type {
+  EmbeddingModelUsage,
   Memory,
   Recipe,
   RecipeRequest,
 } from '../models/index';
 import {
+    EmbeddingModelUsageFromJSON,
+    EmbeddingModelUsageToJSON,
     MemoryFromJSON,
     MemoryToJSON,
     RecipeFromJSON,
@@ -42,6 +45,9 @@ export interface SearchRecipesRequest {
     memory: string;
     tag?: Array<string>;
     q?: string;
+    limit?: number;
+    offset?: number;
+    maxDistance?: number;
 }
 
 export interface UpdateRecipeRequest {
@@ -155,6 +161,43 @@ export class RecipesApi extends runtime.BaseAPI {
         await this.deleteRecipeRaw(requestParameters, initOverrides);
     }
 
+    /**
+     * counts, per memory slot, the recipes embedded by each embedding model
+     */
+    async listRecipesEmbeddingModelsRaw(initOverrides?: RequestInit | runtime.InitOverrideFunction): Promise<runtime.ApiResponse<{ [key: string]: Array<EmbeddingModelUsage>; }>> {
+        const queryParameters: any = {};
+
+        const headerParameters: runtime.HTTPHeaders = {};
+
+        if (this.configuration && this.configuration.accessToken) {
+            const token = this.configuration.accessToken;
+            const tokenString = await token("bearerAuth", []);
+
+            if (tokenString) {
+                headerParameters["Authorization"] = `Bearer ${tokenString}`;
+            }
+        }
+
+        let urlPath = `/recipes/_embedding_models`;
+
+        const response = await this.request({
+            path: urlPath,
+            method: 'GET',
+            headers: headerParameters,
+            query: queryParameters,
+        }, initOverrides);
+
+        return new runtime.JSONApiResponse<any>(response);
+    }
+
+    /**
+     * counts, per memory slot, the recipes embedded by each embedding model
+     */
+    async listRecipesEmbeddingModels(initOverrides?: RequestInit | runtime.InitOverrideFunction): Promise<{ [key: string]: Array<EmbeddingModelUsage>; }> {
+        const response = await this.listRecipesEmbeddingModelsRaw(initOverrides);
+        return await response.value();
+    }
+
     /**
      * lists all the recipes memory slots, and their available tags
      */
@@ -213,6 +256,18 @@ export class RecipesApi extends runtime.BaseAPI {
             queryParameters['q'] = requestParameters['q'];
         }
 
+        if (requestParameters['limit'] != null) {
+            queryParameters['limit'] = requestParameters['limit'];
+        }
+
+        if (requestParameters['offset'] != null) {
+            queryParameters['offset'] = requestParameters['offset'];
+        }
+
+        if (requestParameters['maxDistance'] != null) {
+            queryParameters['max_distance'] = requestParameters['maxDistance'];
+        }
+
         const headerParameters: runtime.HTTPHeaders = {};
 
         if (this.configuration && this.configuration.accessToken) {
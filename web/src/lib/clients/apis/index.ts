@@ -1,5 +1,6 @@
 /* tslint:disable */
 /* eslint-disable */
+export * from './JobsApi';
 export * from './KbApi';
 export * from './ObjectsApi';
 export * from './RecipesApi';
@@ -0,0 +1,84 @@
+/* tslint:disable */
+/* eslint-disable */
+/**
+ * Meta OpenAPI
+ * No description provided (generated by Openapi Generator https://github.com/openapitools/openapi-generator)
+ *
+ * The version of the OpenAPI document: v1
+ * 
+ *
+ * NOTE: This class is auto generated by OpenAPI Generator (https://openapi-generator.tech).
+ * https://openapi-generator.tech
+ * Do not edit the class manually.
+ */
+
+import { mapValues } from '../runtime';
+/**
+ * the chunk merged with its neighbours, when a context window is requested
+ * @export
+ * @interface ChunkContext
+ */
+export interface ChunkContext {
+    /**
+     * 
+     * @type {string}
+     * @memberof ChunkContext
+     */
+    chunk: string;
+    /**
+     * 
+     * @type {number}
+     * @memberof ChunkContext
+     */
+    startOrdinal: number;
+    /**
+     * 
+     * @type {number}
+     * @memberof ChunkContext
+     */
+    endOrdinal: number;
+}
+
+/**
+ * Check if a given object implements the ChunkContext interface.
+ */
+export function instanceOfChunkContext(value: object): value is ChunkContext {
+    if (!('chunk' in value) || value['chunk'] === undefined) return false;
+    if (!('startOrdinal' in value) || value['startOrdinal'] === undefined) return false;
+    if (!('endOrdinal' in value) || value['endOrdinal'] === undefined) return false;
+    return true;
+}
+
+export function ChunkContextFromJSON(json: any): ChunkContext {
+    return ChunkContextFromJSONTyped(json, false);
+}
+
+export function ChunkContextFromJSONTyped(json: any, ignoreDiscriminator: boolean): ChunkContext {
+    if (json == null) {
+        return json;
+    }
+    return {
+        
+        'chunk': json['chunk'],
+        'startOrdinal': json['start_ordinal'],
+        'endOrdinal': json['end_ordinal'],
+    };
+}
+
+export function ChunkContextToJSON(json: any): ChunkContext {
+    return ChunkContextToJSONTyped(json, false);
+}
+
+export function ChunkContextToJSONTyped(value?: ChunkContext | null, ignoreDiscriminator: boolean = false): any {
+    if (value == null) {
+        return value;
+    }
+
+    return {
+        
+        'chunk': value['chunk'],
+        'start_ordinal': value['startOrdinal'],
+        'end_ordinal': value['endOrdinal'],
+    };
+}
+
@@ -0,0 +1,142 @@
+/* tslint:disable */
+/* eslint-disable */
+/**
+ * Meta OpenAPI
+ * No description provided (generated by Openapi Generator https://github.com/openapitools/openapi-generator)
+ *
+ * The version of the OpenAPI document: v1
+ * 
+ *
+ * NOTE: This class is auto generated by OpenAPI Generator (https://openapi-generator.tech).
+ * https://openapi-generator.tech
+ * Do not edit the class manually.
+ */
+
+import { mapValues } from '../runtime';
+/**
+ * how documents are split into chunks. Unset fields fall back to the settings of the memory slot, then to the server defaults
+ * @export
+ * @interface ChunkingSettings
+ */
+export interface ChunkingSettings {
+    /**
+     * `auto` splits markdown documents by heading and everything else recursively, `recursive` by paragraphs, lines and words until chunks fit, `markdown` by heading, `sentence` and `paragraph` pack whole sentences or paragraphs, `semantic` cuts between sentences where embedding similarity drops, `code` by function, type and class definitions. `auto` picks `code` for source files
+     * @type {string}
+     * @memberof ChunkingSettings
+     */
+    strategy?: ChunkingSettingsStrategyEnum;
+    /**
+     * the maximum size of a chunk, in the unit
+     * @type {number}
+     * @memberof ChunkingSettings
+     */
+    size?: number;
+    /**
+     * how much of the end of a chunk is repeated at the start of the next, in the unit. Semantic chunks do not overlap
+     * @type {number}
+     * @memberof ChunkingSettings
+     */
+    overlap?: number;
+    /**
+     * how size and overlap are measured
+     * @type {string}
+     * @memberof ChunkingSettings
+     */
+    unit?: ChunkingSettingsUnitEnum;
+    /**
+     * the tokenizer measuring tokens
+     * @type {string}
+     * @memberof ChunkingSettings
+     */
+    encoding?: ChunkingSettingsEncodingEnum;
+    /**
+     * semantic chunking cuts where the distance between neighbouring sentences is above this percentile of all such distances. Higher values give fewer, larger chunks
+     * @type {number}
+     * @memberof ChunkingSettings
+     */
+    breakpointPercentile?: number;
+}
+
+
+/**
+ * @export
+ */
+export const ChunkingSettingsStrategyEnum = {
+    Auto: 'auto',
+    Recursive: 'recursive',
+    Markdown: 'markdown',
+    Sentence: 'sentence',
+    Paragraph: 'paragraph',
+    Semantic: 'semantic',
+    Code: 'code'
+} as const;
+export type ChunkingSettingsStrategyEnum = typeof ChunkingSettingsStrategyEnum[keyof typeof ChunkingSettingsStrategyEnum];
+
+
+/**
+ * @export
+ */
+export const ChunkingSettingsUnitEnum = {
+    Characters: 'characters',
+    Tokens: 'tokens'
+} as const;
+export type ChunkingSettingsUnitEnum = typeof ChunkingSettingsUnitEnum[keyof typeof ChunkingSettingsUnitEnum];
+
+
+/**
+ * @export
+ */
+export const ChunkingSettingsEncodingEnum = {
+    Cl100kBase: 'cl100k_base',
+    P50kBase: 'p50k_base',
+    R50kBase: 'r50k_base'
+} as const;
+export type ChunkingSettingsEncodingEnum = typeof ChunkingSettingsEncodingEnum[keyof typeof ChunkingSettingsEncodingEnum];
+
+
+/**
+ * Check if a given object implements the ChunkingSettings interface.
+ */
+export function instanceOfChunkingSettings(value: object): value is ChunkingSettings {
+    return true;
+}
+
+export function ChunkingSettingsFromJSON(json: any): ChunkingSettings {
+    return ChunkingSettingsFromJSONTyped(json, false);
+}
+
+export function ChunkingSettingsFromJSONTyped(json: any, ignoreDiscriminator: boolean): ChunkingSettings {
+    if (json == null) {
+        return json;
+    }
+    return {
+        
+        'strategy': json['strategy'] == null ? undefined : json['strategy'],
+        'size': json['size'] == null ? undefined : json['size'],
+        'overlap': json['overlap'] == null ? undefined : json['overlap'],
+        'unit': json['unit'] == null ? undefined : json['unit'],
+        'encoding': json['encoding'] == null ? undefined : json['encoding'],
+        'breakpointPercentile': json['breakpoint_percentile'] == null ? undefined : json['breakpoint_percentile'],
+    };
+}
+
+export function ChunkingSettingsToJSON(json: any): ChunkingSettings {
+    return ChunkingSettingsToJSONTyped(json, false);
+}
+
+export function ChunkingSettingsToJSONTyped(value?: ChunkingSettings | null, ignoreDiscriminator: boolean = false): any {
+    if (value == null) {
+        return value;
+    }
+
+    return {
+        
+        'strategy': value['strategy'],
+        'size': value['size'],
+        'overlap': value['overlap'],
+        'unit': value['unit'],
+        'encoding': value['encoding'],
+        'breakpoint_percentile': value['breakpointPercentile'],
+    };
+}
+
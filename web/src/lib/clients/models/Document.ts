@@ -13,6 +13,14 @@
  */
 
 import { mapValues } from '../runtime';
+import type { ChunkingSettings } from './ChunkingSettings';
+import {
+    ChunkingSettingsFromJSON,
+    ChunkingSettingsFromJSONTyped,
+    ChunkingSettingsToJSON,
+    ChunkingSettingsToJSONTyped,
+} from './ChunkingSettings';
+
 /**
  * 
  * @export
@@ -31,6 +39,12 @@ export interface Document {
      * @memberof Document
      */
     content: string;
+    /**
+     * 
+     * @type {ChunkingSettings}
+     * @memberof Document
+     */
+    chunking?: ChunkingSettings;
 }
 
 /**
@@ -54,6 +68,7 @@ export function DocumentFromJSONTyped(json: any, ignoreDiscriminator: boolean):
         
         'tags': json['tags'],
         'content': json['content'],
+        'chunking': json['chunking'] == null ? undefined : ChunkingSettingsFromJSON(json['chunking']),
     };
 }
 
@@ -70,6 +85,7 @@ export function DocumentToJSONTyped(value?: Document | null, ignoreDiscriminator
         
         'tags': value['tags'],
         'content': value['content'],
+        'chunking': ChunkingSettingsToJSON(value['chunking']),
     };
 }
 
@@ -0,0 +1,153 @@
+/* tslint:disable */
+/* eslint-disable */
+/**
+ * Meta OpenAPI
+ * No description provided (generated by Openapi Generator https://github.com/openapitools/openapi-generator)
+ *
+ * The version of the OpenAPI document: v1
+ * 
+ *
+ * NOTE: This class is auto generated by OpenAPI Generator (https://openapi-generator.tech).
+ * https://openapi-generator.tech
+ * Do not edit the class manually.
+ */
+
+import { mapValues } from '../runtime';
+import type { KnowledgeDocument } from './KnowledgeDocument';
+import {
+    KnowledgeDocumentFromJSON,
+    KnowledgeDocumentFromJSONTyped,
+    KnowledgeDocumentToJSON,
+    KnowledgeDocumentToJSONTyped,
+} from './KnowledgeDocument';
+
+/**
+ * 
+ * @export
+ * @interface DocumentContent
+ */
+export interface DocumentContent {
+    /**
+     * 
+     * @type {KnowledgeDocument}
+     * @memberof DocumentContent
+     */
+    document: KnowledgeDocument;
+    /**
+     * the text of the document within the range
+     * @type {string}
+     * @memberof DocumentContent
+     */
+    content: string;
+    /**
+     * the name of the file the document was uploaded as
+     * @type {string}
+     * @memberof DocumentContent
+     */
+    fileName?: string;
+    /**
+     * the media type of the file the document was uploaded as, detected from its name and content
+     * @type {string}
+     * @memberof DocumentContent
+     */
+    mediaType?: string;
+    /**
+     * 
+     * @type {number}
+     * @memberof DocumentContent
+     */
+    startByte: number;
+    /**
+     * the byte the content ends before
+     * @type {number}
+     * @memberof DocumentContent
+     */
+    endByte: number;
+    /**
+     * 
+     * @type {number}
+     * @memberof DocumentContent
+     */
+    startLine: number;
+    /**
+     * 
+     * @type {number}
+     * @memberof DocumentContent
+     */
+    endLine: number;
+    /**
+     * the size of the whole text
+     * @type {number}
+     * @memberof DocumentContent
+     */
+    totalBytes: number;
+    /**
+     * 
+     * @type {number}
+     * @memberof DocumentContent
+     */
+    totalLines: number;
+}
+
+/**
+ * Check if a given object implements the DocumentContent interface.
+ */
+export function instanceOfDocumentContent(value: object): value is DocumentContent {
+    if (!('document' in value) || value['document'] === undefined) return false;
+    if (!('content' in value) || value['content'] === undefined) return false;
+    if (!('startByte' in value) || value['startByte'] === undefined) return false;
+    if (!('endByte' in value) || value['endByte'] === undefined) return false;
+    if (!('startLine' in value) || value['startLine'] === undefined) return false;
+    if (!('endLine' in value) || value['endLine'] === undefined) return false;
+    if (!('totalBytes' in value) || value['totalBytes'] === undefined) return false;
+    if (!('totalLines' in value) || value['totalLines'] === undefined) return false;
+    return true;
+}
+
+export function DocumentContentFromJSON(json: any): DocumentContent {
+    return DocumentContentFromJSONTyped(json, false);
+}
+
+export function DocumentContentFromJSONTyped(json: any, ignoreDiscriminator: boolean): DocumentContent {
+    if (json == null) {
+        return json;
+    }
+    return {
+        
+        'document': KnowledgeDocumentFromJSON(json['document']),
+        'content': json['content'],
+        'fileName': json['file_name'] == null ? undefined : json['file_name'],
+        'mediaType': json['media_type'] == null ? undefined : json['media_type'],
+        'startByte': json['start_byte'],
+        'endByte': json['end_byte'],
+        'startLine': json['start_line'],
+        'endLine': json['end_line'],
+        'totalBytes': json['total_bytes'],
+        'totalLines': json['total_lines'],
+    };
+}
+
+export function DocumentContentToJSON(json: any): DocumentContent {
+    return DocumentContentToJSONTyped(json, false);
+}
+
+export function DocumentContentToJSONTyped(value?: DocumentContent | null, ignoreDiscriminator: boolean = false): any {
+    if (value == null) {
+        return value;
+    }
+
+    return {
+        
+        'document': KnowledgeDocumentToJSON(value['document']),
+        'content': value['content'],
+        'file_name': value['fileName'],
+        'media_type': value['mediaType'],
+        'start_byte': value['startByte'],
+        'end_byte': value['endByte'],
+        'start_line': value['startLine'],
+        'end_line': value['endLine'],
+        'total_bytes': value['totalBytes'],
+        'total_lines': value['totalLines'],
+    };
+}
+
@@ -0,0 +1,82 @@
+/* tslint:disable */
+/* eslint-disable */
+/**
+ * Meta OpenAPI
+ * No description provided (generated by Openapi Generator https://github.com/openapitools/openapi-generator)
+ *
+ * The version of the OpenAPI document: v1
+ * 
+ *
+ * NOTE: This class is auto generated by OpenAPI Generator (https://openapi-generator.tech).
+ * https://openapi-generator.tech
+ * Do not edit the class manually.
+ */
+
+import { mapValues } from '../runtime';
+/**
+ * 
+ * @export
+ * @interface DocumentUpload
+ */
+export interface DocumentUpload {
+    /**
+     * 
+     * @type {Blob}
+     * @memberof DocumentUpload
+     */
+    file: Blob;
+    /**
+     * 
+     * @type {Array<string>}
+     * @memberof DocumentUpload
+     */
+    tags?: Array<string>;
+    /**
+     * JSON encoded chunking settings, overriding the ones of the memory slot for this document
+     * @type {string}
+     * @memberof DocumentUpload
+     */
+    chunking?: string;
+}
+
+/**
+ * Check if a given object implements the DocumentUpload interface.
+ */
+export function instanceOfDocumentUpload(value: object): value is DocumentUpload {
+    if (!('file' in value) || value['file'] === undefined) return false;
+    return true;
+}
+
+export function DocumentUploadFromJSON(json: any): DocumentUpload {
+    return DocumentUploadFromJSONTyped(json, false);
+}
+
+export function DocumentUploadFromJSONTyped(json: any, ignoreDiscriminator: boolean): DocumentUpload {
+    if (json == null) {
+        return json;
+    }
+    return {
+        
+        'file': json['file'],
+        'tags': json['tags'] == null ? undefined : json['tags'],
+        'chunking': json['chunking'] == null ? undefined : json['chunking'],
+    };
+}
+
+export function DocumentUploadToJSON(json: any): DocumentUpload {
+    return DocumentUploadToJSONTyped(json, false);
+}
+
+export function DocumentUploadToJSONTyped(value?: DocumentUpload | null, ignoreDiscriminator: boolean = false): any {
+    if (value == null) {
+        return value;
+    }
+
+    return {
+        
+        'file': value['file'],
+        'tags': value['tags'],
+        'chunking': value['chunking'],
+    };
+}
+
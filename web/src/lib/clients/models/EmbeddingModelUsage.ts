@@ -0,0 +1,93 @@
+/* tslint:disable */
+/* eslint-disable */
+/**
+ * Meta OpenAPI
+ * No description provided (generated by Openapi Generator https://github.com/openapitools/openapi-generator)
+ *
+ * The version of the OpenAPI document: v1
+ * 
+ *
+ * NOTE: This class is auto generated by OpenAPI Generator (https://openapi-generator.tech).
+ * https://openapi-generator.tech
+ * Do not edit the class manually.
+ */
+
+import { mapValues } from '../runtime';
+/**
+ * 
+ * @export
+ * @interface EmbeddingModelUsage
+ */
+export interface EmbeddingModelUsage {
+    /**
+     * 
+     * @type {string}
+     * @memberof EmbeddingModelUsage
+     */
+    provider: string;
+    /**
+     * 
+     * @type {string}
+     * @memberof EmbeddingModelUsage
+     */
+    model: string;
+    /**
+     * 
+     * @type {number}
+     * @memberof EmbeddingModelUsage
+     */
+    count: number;
+    /**
+     * whether this is the configured model, the only one searches compare against
+     * @type {boolean}
+     * @memberof EmbeddingModelUsage
+     */
+    current: boolean;
+}
+
+/**
+ * Check if a given object implements the EmbeddingModelUsage interface.
+ */
+export function instanceOfEmbeddingModelUsage(value: object): value is EmbeddingModelUsage {
+    if (!('provider' in value) || value['provider'] === undefined) return false;
+    if (!('model' in value) || value['model'] === undefined) return false;
+    if (!('count' in value) || value['count'] === undefined) return false;
+    if (!('current' in value) || value['current'] === undefined) return false;
+    return true;
+}
+
+export function EmbeddingModelUsageFromJSON(json: any): EmbeddingModelUsage {
+    return EmbeddingModelUsageFromJSONTyped(json, false);
+}
+
+export function EmbeddingModelUsageFromJSONTyped(json: any, ignoreDiscriminator: boolean): EmbeddingModelUsage {
+    if (json == null) {
+        return json;
+    }
+    return {
+        
+        'provider': json['provider'],
+        'model': json['model'],
+        'count': json['count'],
+        'current': json['current'],
+    };
+}
+
+export function EmbeddingModelUsageToJSON(json: any): EmbeddingModelUsage {
+    return EmbeddingModelUsageToJSONTyped(json, false);
+}
+
+export function EmbeddingModelUsageToJSONTyped(value?: EmbeddingModelUsage | null, ignoreDiscriminator: boolean = false): any {
+    if (value == null) {
+        return value;
+    }
+
+    return {
+        
+        'provider': value['provider'],
+        'model': value['model'],
+        'count': value['count'],
+        'current': value['current'],
+    };
+}
+
@@ -0,0 +1,174 @@
+/* tslint:disable */
+/* eslint-disable */
+/**
+ * Meta OpenAPI
+ * No description provided (generated by Openapi Generator https://github.com/openapitools/openapi-generator)
+ *
+ * The version of the OpenAPI document: v1
+ * 
+ *
+ * NOTE: This class is auto generated by OpenAPI Generator (https://openapi-generator.tech).
+ * https://openapi-generator.tech
+ * Do not edit the class manually.
+ */
+
+import { mapValues } from '../runtime';
+/**
+ * 
+ * @export
+ * @interface IngestionJob
+ */
+export interface IngestionJob {
+    /**
+     * 
+     * @type {string}
+     * @memberof IngestionJob
+     */
+    id: string;
+    /**
+     * 
+     * @type {string}
+     * @memberof IngestionJob
+     */
+    memory: string;
+    /**
+     * 
+     * @type {string}
+     * @memberof IngestionJob
+     */
+    document: string;
+    /**
+     * a failed attempt is queued again, unless it cannot succeed or no attempts are left
+     * @type {string}
+     * @memberof IngestionJob
+     */
+    state: IngestionJobStateEnum;
+    /**
+     * 
+     * @type {number}
+     * @memberof IngestionJob
+     */
+    attempts: number;
+    /**
+     * the chunks of the document that need embedding. Chunks whose content did not change are not embedded again
+     * @type {number}
+     * @memberof IngestionJob
+     */
+    chunksToEmbed: number;
+    /**
+     * 
+     * @type {number}
+     * @memberof IngestionJob
+     */
+    chunksEmbedded: number;
+    /**
+     * the error of the last failed attempt
+     * @type {string}
+     * @memberof IngestionJob
+     */
+    error?: string;
+    /**
+     * the version of the document once recorded
+     * @type {number}
+     * @memberof IngestionJob
+     */
+    documentVersion?: number;
+    /**
+     * 
+     * @type {Date}
+     * @memberof IngestionJob
+     */
+    createdAt: Date;
+    /**
+     * 
+     * @type {Date}
+     * @memberof IngestionJob
+     */
+    startedAt?: Date;
+    /**
+     * 
+     * @type {Date}
+     * @memberof IngestionJob
+     */
+    finishedAt?: Date;
+}
+
+
+/**
+ * @export
+ */
+export const IngestionJobStateEnum = {
+    Queued: 'queued',
+    Running: 'running',
+    Succeeded: 'succeeded',
+    Failed: 'failed'
+} as const;
+export type IngestionJobStateEnum = typeof IngestionJobStateEnum[keyof typeof IngestionJobStateEnum];
+
+
+/**
+ * Check if a given object implements the IngestionJob interface.
+ */
+export function instanceOfIngestionJob(value: object): value is IngestionJob {
+    if (!('id' in value) || value['id'] === undefined) return false;
+    if (!('memory' in value) || value['memory'] === undefined) return false;
+    if (!('document' in value) || value['document'] === undefined) return false;
+    if (!('state' in value) || value['state'] === undefined) return false;
+    if (!('attempts' in value) || value['attempts'] === undefined) return false;
+    if (!('chunksToEmbed' in value) || value['chunksToEmbed'] === undefined) return false;
+    if (!('chunksEmbedded' in value) || value['chunksEmbedded'] === undefined) return false;
+    if (!('createdAt' in value) || value['createdAt'] === undefined) return false;
+    return true;
+}
+
+export function IngestionJobFromJSON(json: any): IngestionJob {
+    return IngestionJobFromJSONTyped(json, false);
+}
+
+export function IngestionJobFromJSONTyped(json: any, ignoreDiscriminator: boolean): IngestionJob {
+    if (json == null) {
+        return json;
+    }
+    return {
+        
+        'id': json['id'],
+        'memory': json['memory'],
+        'document': json['document'],
+        'state': json['state'],
+        'attempts': json['attempts'],
+        'chunksToEmbed': json['chunks_to_embed'],
+        'chunksEmbedded': json['chunks_embedded'],
+        'error': json['error'] == null ? undefined : json['error'],
+        'documentVersion': json['document_version'] == null ? undefined : json['document_version'],
+        'createdAt': (new Date(json['created_at'])),
+        'startedAt': json['started_at'] == null ? undefined : (new Date(json['started_at'])),
+        'finishedAt': json['finished_at'] == null ? undefined : (new Date(json['finished_at'])),
+    };
+}
+
+export function IngestionJobToJSON(json: any): IngestionJob {
+    return IngestionJobToJSONTyped(json, false);
+}
+
+export function IngestionJobToJSONTyped(value?: IngestionJob | null, ignoreDiscriminator: boolean = false): any {
+    if (value == null) {
+        return value;
+    }
+
+    return {
+        
+        'id': value['id'],
+        'memory': value['memory'],
+        'document': value['document'],
+        'state': value['state'],
+        'attempts': value['attempts'],
+        'chunks_to_embed': value['chunksToEmbed'],
+        'chunks_embedded': value['chunksEmbedded'],
+        'error': value['error'],
+        'document_version': value['documentVersion'],
+        'created_at': ((value['createdAt']).toISOString()),
+        'started_at': value['startedAt'] == null ? undefined : ((value['startedAt']).toISOString()),
+        'finished_at': value['finishedAt'] == null ? undefined : ((value['finishedAt']).toISOString()),
+    };
+}
+
@@ -13,12 +13,32 @@
  */
 
 import { mapValues } from '../runtime';
+import type { ChunkContext } from './ChunkContext';
+import {
+    ChunkContextFromJSON,
+    ChunkContextFromJSONTyped,
+    ChunkContextToJSON,
+    ChunkContextToJSONTyped,
+} from './ChunkContext';
+
 /**
  * 
  * @export
  * @interface KnowledgeChunk
  */
 export interface KnowledgeChunk {
+    /**
+     * identifies the chunk, to cite it and fetch it again. It stays the same as long as the text of the chunk does, across submissions of its document
+     * @type {string}
+     * @memberof KnowledgeChunk
+     */
+    id: string;
+    /**
+     * 
+     * @type {string}
+     * @memberof KnowledgeChunk
+     */
+    memory: string;
     /**
      * 
      * @type {string}
@@ -37,12 +57,68 @@ export interface KnowledgeChunk {
      * @memberof KnowledgeChunk
      */
     chunk: string;
+    /**
+     * the cosine distance to the query. Absent in keyword searches
+     * @type {number}
+     * @memberof KnowledgeChunk
+     */
+    distance?: number;
+    /**
+     * the function, type or class a chunk of source code defines
+     * @type {string}
+     * @memberof KnowledgeChunk
+     */
+    symbol?: string;
+    /**
+     * the first line of a chunk of source code in its document, starting from 1
+     * @type {number}
+     * @memberof KnowledgeChunk
+     */
+    startLine?: number;
+    /**
+     * the last line of a chunk of source code in its document
+     * @type {number}
+     * @memberof KnowledgeChunk
+     */
+    endLine?: number;
+    /**
+     * the position of the chunk in its document, starting from 0. Absent for chunks recorded before positions were tracked
+     * @type {number}
+     * @memberof KnowledgeChunk
+     */
+    ordinal?: number;
+    /**
+     * the byte offset where the chunk starts in the text of its document, to be read back as the start_byte of a document range
+     * @type {number}
+     * @memberof KnowledgeChunk
+     */
+    startOffset?: number;
+    /**
+     * the byte offset where the chunk ends in the text of its document, exclusive, to be read back as the end_byte of a document range
+     * @type {number}
+     * @memberof KnowledgeChunk
+     */
+    endOffset?: number;
+    /**
+     * the headings the chunk falls under, from the outermost
+     * @type {Array<string>}
+     * @memberof KnowledgeChunk
+     */
+    headings?: Array<string>;
+    /**
+     * 
+     * @type {ChunkContext}
+     * @memberof KnowledgeChunk
+     */
+    context?: ChunkContext;
 }
 
 /**
  * Check if a given object implements the KnowledgeChunk interface.
  */
 export function instanceOfKnowledgeChunk(value: object): value is KnowledgeChunk {
+    if (!('id' in value) || value['id'] === undefined) return false;
+    if (!('memory' in value) || value['memory'] === undefined) return false;
     if (!('document' in value) || value['document'] === undefined) return false;
     if (!('tags' in value) || value['tags'] === undefined) return false;
     if (!('chunk' in value) || value['chunk'] === undefined) return false;
@@ -59,9 +135,20 @@ export function KnowledgeChunkFromJSONTyped(json: any, ignoreDiscriminator: bool
     }
     return {
         
+        'id': json['id'],
+        'memory': json['memory'],
         'document': json['document'],
         'tags': json['tags'],
         'chunk': json['chunk'],
+        'distance': json['distance'] == null ? undefined : json['distance'],
+        'symbol': json['symbol'] == null ? undefined : json['symbol'],
+        'startLine': json['start_line'] == null ? undefined : json['start_line'],
+        'endLine': json['end_line'] == null ? undefined : json['end_line'],
+        'ordinal': json['ordinal'] == null ? undefined : json['ordinal'],
+        'startOffset': json['start_offset'] == null ? undefined : json['start_offset'],
+        'endOffset': json['end_offset'] == null ? undefined : json['end_offset'],
+        'headings': json['headings'] == null ? undefined : json['headings'],
+        'context': json['context'] == null ? undefined : ChunkContextFromJSON(json['context']),
     };
 }
 
@@ -76,9 +163,20 @@ export function KnowledgeChunkToJSONTyped(value?: KnowledgeChunk | null, ignoreD
 
     return {
         
+        'id': value['id'],
+        'memory': value['memory'],
         'document': value['document'],
         'tags': value['tags'],
         'chunk': value['chunk'],
+        'distance': value['distance'],
+        'symbol': value['symbol'],
+        'start_line': value['startLine'],
+        'end_line': value['endLine'],
+        'ordinal': value['ordinal'],
+        'start_offset': value['startOffset'],
+        'end_offset': value['endOffset'],
+        'headings': value['headings'],
+        'context': ChunkContextToJSON(value['context']),
     };
 }
 
@@ -0,0 +1,164 @@
+/* tslint:disable */
+/* eslint-disable */
+/**
+ * Meta OpenAPI
+ * No description provided (generated by Openapi Generator https://github.com/openapitools/openapi-generator)
+ *
+ * The version of the OpenAPI document: v1
+ * 
+ *
+ * NOTE: This class is auto generated by OpenAPI Generator (https://openapi-generator.tech).
+ * https://openapi-generator.tech
+ * Do not edit the class manually.
+ */
+
+import { mapValues } from '../runtime';
+import type { ChunkingSettings } from './ChunkingSettings';
+import {
+    ChunkingSettingsFromJSON,
+    ChunkingSettingsFromJSONTyped,
+    ChunkingSettingsToJSON,
+    ChunkingSettingsToJSONTyped,
+} from './ChunkingSettings';
+
+/**
+ * 
+ * @export
+ * @interface KnowledgeDocument
+ */
+export interface KnowledgeDocument {
+    /**
+     * 
+     * @type {string}
+     * @memberof KnowledgeDocument
+     */
+    id: string;
+    /**
+     * 
+     * @type {string}
+     * @memberof KnowledgeDocument
+     */
+    memory: string;
+    /**
+     * 
+     * @type {string}
+     * @memberof KnowledgeDocument
+     */
+    name: string;
+    /**
+     * 
+     * @type {Array<string>}
+     * @memberof KnowledgeDocument
+     */
+    tags: Array<string>;
+    /**
+     * the SHA-256 of the extracted text. Empty for documents recorded before it was tracked
+     * @type {string}
+     * @memberof KnowledgeDocument
+     */
+    contentHash: string;
+    /**
+     * the size of the extracted text, in bytes
+     * @type {number}
+     * @memberof KnowledgeDocument
+     */
+    size: number;
+    /**
+     * 
+     * @type {number}
+     * @memberof KnowledgeDocument
+     */
+    chunkCount: number;
+    /**
+     * grows every time the document is submitted with changes
+     * @type {number}
+     * @memberof KnowledgeDocument
+     */
+    version: number;
+    /**
+     * 
+     * @type {ChunkingSettings}
+     * @memberof KnowledgeDocument
+     */
+    chunking: ChunkingSettings;
+    /**
+     * 
+     * @type {Date}
+     * @memberof KnowledgeDocument
+     */
+    createdAt: Date;
+    /**
+     * 
+     * @type {Date}
+     * @memberof KnowledgeDocument
+     */
+    updatedAt: Date;
+}
+
+/**
+ * Check if a given object implements the KnowledgeDocument interface.
+ */
+export function instanceOfKnowledgeDocument(value: object): value is KnowledgeDocument {
+    if (!('id' in value) || value['id'] === undefined) return false;
+    if (!('memory' in value) || value['memory'] === undefined) return false;
+    if (!('name' in value) || value['name'] === undefined) return false;
+    if (!('tags' in value) || value['tags'] === undefined) return false;
+    if (!('contentHash' in value) || value['contentHash'] === undefined) return false;
+    if (!('size' in value) || value['size'] === undefined) return false;
+    if (!('chunkCount' in value) || value['chunkCount'] === undefined) return false;
+    if (!('version' in value) || value['version'] === undefined) return false;
+    if (!('chunking' in value) || value['chunking'] === undefined) return false;
+    if (!('createdAt' in value) || value['createdAt'] === undefined) return false;
+    if (!('updatedAt' in value) || value['updatedAt'] === undefined) return false;
+    return true;
+}
+
+export function KnowledgeDocumentFromJSON(json: any): KnowledgeDocument {
+    return KnowledgeDocumentFromJSONTyped(json, false);
+}
+
+export function KnowledgeDocumentFromJSONTyped(json: any, ignoreDiscriminator: boolean): KnowledgeDocument {
+    if (json == null) {
+        return json;
+    }
+    return {
+        
+        'id': json['id'],
+        'memory': json['memory'],
+        'name': json['name'],
+        'tags': json['tags'],
+        'contentHash': json['content_hash'],
+        'size': json['size'],
+        'chunkCount': json['chunk_count'],
+        'version': json['version'],
+        'chunking': ChunkingSettingsFromJSON(json['chunking']),
+        'createdAt': (new Date(json['created_at'])),
+        'updatedAt': (new Date(json['updated_at'])),
+    };
+}
+
+export function KnowledgeDocumentToJSON(json: any): KnowledgeDocument {
+    return KnowledgeDocumentToJSONTyped(json, false);
+}
+
+export function KnowledgeDocumentToJSONTyped(value?: KnowledgeDocument | null, ignoreDiscriminator: boolean = false): any {
+    if (value == null) {
+        return value;
+    }
+
+    return {
+        
+        'id': value['id'],
+        'memory': value['memory'],
+        'name': value['name'],
+        'tags': value['tags'],
+        'content_hash': value['contentHash'],
+        'size': value['size'],
+        'chunk_count': value['chunkCount'],
+        'version': value['version'],
+        'chunking': ChunkingSettingsToJSON(value['chunking']),
+        'created_at': ((value['createdAt']).toISOString()),
+        'updated_at': ((value['updatedAt']).toISOString()),
+    };
+}
+
@@ -0,0 +1,73 @@
+/* tslint:disable */
+/* eslint-disable */
+/**
+ * Meta OpenAPI
+ * No description provided (generated by Openapi Generator https://github.com/openapitools/openapi-generator)
+ *
+ * The version of the OpenAPI document: v1
+ * 
+ *
+ * NOTE: This class is auto generated by OpenAPI Generator (https://openapi-generator.tech).
+ * https://openapi-generator.tech
+ * Do not edit the class manually.
+ */
+
+import { mapValues } from '../runtime';
+import type { ChunkingSettings } from './ChunkingSettings';
+import {
+    ChunkingSettingsFromJSON,
+    ChunkingSettingsFromJSONTyped,
+    ChunkingSettingsToJSON,
+    ChunkingSettingsToJSONTyped,
+} from './ChunkingSettings';
+
+/**
+ * 
+ * @export
+ * @interface MemorySettings
+ */
+export interface MemorySettings {
+    /**
+     * 
+     * @type {ChunkingSettings}
+     * @memberof MemorySettings
+     */
+    chunking?: ChunkingSettings;
+}
+
+/**
+ * Check if a given object implements the MemorySettings interface.
+ */
+export function instanceOfMemorySettings(value: object): value is MemorySettings {
+    return true;
+}
+
+export function MemorySettingsFromJSON(json: any): MemorySettings {
+    return MemorySettingsFromJSONTyped(json, false);
+}
+
+export function MemorySettingsFromJSONTyped(json: any, ignoreDiscriminator: boolean): MemorySettings {
+    if (json == null) {
+        return json;
+    }
+    return {
+        
+        'chunking': json['chunking'] == null ? undefined : ChunkingSettingsFromJSON(json['chunking']),
+    };
+}
+
+export function MemorySettingsToJSON(json: any): MemorySettings {
+    return MemorySettingsToJSONTyped(json, false);
+}
+
+export function MemorySettingsToJSONTyped(value?: MemorySettings | null, ignoreDiscriminator: boolean = false): any {
+    if (value == null) {
+        return value;
+    }
+
+    return {
+        
+        'chunking': ChunkingSettingsToJSON(value['chunking']),
+    };
+}
+
@@ -49,6 +49,12 @@ export interface Recipe {
      * @memberof Recipe
      */
     id: string;
+    /**
+     * the cosine distance to the query, in search results
+     * @type {number}
+     * @memberof Recipe
+     */
+    distance?: number;
 }
 
 /**
@@ -78,6 +84,7 @@ export function RecipeFromJSONTyped(json: any, ignoreDiscriminator: boolean): Re
         'description': json['description'],
         'content': json['content'],
         'id': json['id'],
+        'distance': json['distance'] == null ? undefined : json['distance'],
     };
 }
 
@@ -97,6 +104,7 @@ export function RecipeToJSONTyped(value?: Recipe | null, ignoreDiscriminator: bo
         'description': value['description'],
         'content': value['content'],
         'id': value['id'],
+        'distance': value['distance'],
     };
 }
 
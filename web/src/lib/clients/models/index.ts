@@ -1,8 +1,16 @@
 /* tslint:disable */
 /* eslint-disable */
+export * from './ChunkContext';
+export * from './ChunkingSettings';
 export * from './DataObject';
 export * from './Document';
+export * from './DocumentContent';
+export * from './DocumentUpload';
+export * from './EmbeddingModelUsage';
+export * from './IngestionJob';
 export * from './KnowledgeChunk';
+export * from './KnowledgeDocument';
 export * from './Memory';
+export * from './MemorySettings';
 export * from './Recipe';
 export * from './RecipeRequest';
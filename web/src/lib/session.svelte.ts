@@ -15,7 +15,7 @@
  * along with this program.  If not, see <https://www.gnu.org/licenses/>.
  */
 
-import {Configuration, KbApi, ObjectsApi, RecipesApi} from "./clients";
+import {Configuration, JobsApi, KbApi, ObjectsApi, RecipesApi} from "./clients";
 
 
 
@@ -42,6 +42,14 @@ export function getObjectsClient(): ObjectsApi {
     )
 }
 
+export function getJobsClient(): JobsApi {
+    return new JobsApi(new Configuration({
+            basePath: "/api/v1",
+            accessToken: getAuthorizationKey
+        })
+    )
+}
+
 export function getAuthorizationKey(): string {
     return localStorage.getItem("authorizationKey") ?? ""
 }
\ No newline at end of file